@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add <content>",
+	Short: "Add a task",
+	Long: `Add a task and print it.
+
+Projects and sections can be given by name or ID. Without --project the task
+goes to the default_project setting, or the Inbox. Priorities run from p1
+(urgent) to p4 (normal). Due dates accept natural language like "tomorrow 9am"
+or "every monday", deadlines are YYYY-MM-DD dates.`,
+	Example: `  todoist add "Buy milk" --project Shopping --label errand --due tomorrow
+  todoist add "Write report" --priority p1 --deadline 2026-01-31 --duration 1h30m
+  git log -5 | todoist add "Review changes" --description-file -`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := config.Current()
+		if err != nil {
+			return err
+		}
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		opts := cli.AddOptions{Task: todoist.CreateTaskOptions{Content: strings.Join(args, " ")}}
+		flags := cmd.Flags()
+
+		opts.Project, _ = flags.GetString("project")
+		if opts.Project == "" {
+			opts.Project = settings.DefaultProject
+		}
+		opts.Section, _ = flags.GetString("section")
+		opts.Task.ParentID, _ = flags.GetString("parent")
+		opts.Task.Labels, _ = flags.GetStringArray("label")
+		opts.Task.AssigneeID, _ = flags.GetInt("assignee")
+		opts.Task.DueString, _ = flags.GetString("due")
+		opts.Task.Description, _ = flags.GetString("description")
+
+		if priority, _ := flags.GetString("priority"); priority != "" {
+			if opts.Task.Priority, err = cli.ParsePriority(priority); err != nil {
+				return err
+			}
+		}
+		if deadline, _ := flags.GetString("deadline"); deadline != "" {
+			if opts.Task.DeadlineDate, err = cli.ParseDate(deadline); err != nil {
+				return err
+			}
+		}
+		if duration, _ := flags.GetString("duration"); duration != "" {
+			if opts.Task.Duration, opts.Task.DurationUnit, err = cli.ParseDuration(duration); err != nil {
+				return err
+			}
+		}
+		if path, _ := flags.GetString("description-file"); path != "" {
+			if flags.Changed("description") {
+				return fmt.Errorf("--description and --description-file cannot be used together")
+			}
+			if opts.Task.Description, err = cli.ReadFileArg(path); err != nil {
+				return fmt.Errorf("failed to read description: %w", err)
+			}
+		}
+
+		offline, _ := flags.GetBool("offline")
+		client, journal := newClient(offline, todoist.ScopeTaskAdd)
+		defer reportConflicts(journal)
+
+		opts.Cache = cache.Options{MaxAge: time.Hour, Offline: offline}
+		return cli.Add(cmd.Context(), client, opts, out)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+
+	addCmd.Flags().StringP("project", "p", "", "Project name or ID (default the default_project setting, or the Inbox)")
+	addCmd.Flags().StringP("section", "s", "", "Section name or ID")
+	addCmd.Flags().String("parent", "", "ID of the parent task")
+	addCmd.Flags().StringArrayP("label", "l", nil, "Label to add, can be repeated")
+	addCmd.Flags().String("priority", "", "Priority from p1 (urgent) to p4 (normal)")
+	addCmd.Flags().StringP("due", "d", "", "Due date in natural language, e.g. \"tomorrow 9am\"")
+	addCmd.Flags().String("deadline", "", "Deadline date as YYYY-MM-DD")
+	addCmd.Flags().String("duration", "", "Estimated duration, e.g. 30m, 1h30m or 2d")
+	addCmd.Flags().Int("assignee", 0, "User ID of the assignee in a shared project")
+	addCmd.Flags().String("description", "", "Task description")
+	addCmd.Flags().String("description-file", "", "Read the description from a file, or stdin with -")
+	addCmd.Flags().Bool("json", false, "Output the task as JSON")
+	addCmd.Flags().MarkDeprecated("json", "use --output json instead")
+
+	addCmd.RegisterFlagCompletionFunc("project", completeProjects)
+	addCmd.RegisterFlagCompletionFunc("section", completeSections)
+	addCmd.RegisterFlagCompletionFunc("parent", completeTasks(0))
+	addCmd.RegisterFlagCompletionFunc("label", completeLabels)
+	addCmd.RegisterFlagCompletionFunc("priority", completeList(priorityValues...))
+}
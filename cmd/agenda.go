@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+)
+
+// todayCmd represents the today command
+var todayCmd = &cobra.Command{
+	Use:   "today",
+	Short: "Show overdue tasks and tasks due today",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAgenda(cmd, cli.AgendaOptions{Days: 1, Overdue: true, GroupOverdue: true})
+	},
+}
+
+// upcomingCmd represents the upcoming command
+var upcomingCmd = &cobra.Command{
+	Use:   "upcoming",
+	Short: "Show tasks due in the coming days, grouped by day",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, _ := cmd.Flags().GetInt("days")
+		if days < 1 {
+			days = 1
+		}
+		return runAgenda(cmd, cli.AgendaOptions{Days: days})
+	},
+}
+
+// overdueCmd represents the overdue command
+var overdueCmd = &cobra.Command{
+	Use:   "overdue",
+	Short: "Show overdue tasks, grouped by the day they were due",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAgenda(cmd, cli.AgendaOptions{Overdue: true})
+	},
+}
+
+const agendaLong = `Tasks are grouped by day under weekday headings. Within a day they keep the
+order given in Todoist, then go by priority and time; timed tasks show their
+time in the local timezone. Each heading totals the durations planned for the
+day and flags it as overbooked when they exceed --capacity.`
+
+func runAgenda(cmd *cobra.Command, opts cli.AgendaOptions) error {
+	out, err := outputOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	offline, _ := cmd.Flags().GetBool("offline")
+	client, journal := newClient(offline, todoist.ScopeDataRead)
+	defer reportConflicts(journal)
+
+	opts.Capacity, _ = cmd.Flags().GetDuration("capacity")
+	opts.Cache = cache.Options{MaxAge: 5 * time.Minute, Offline: offline}
+	return cli.Agenda(cmd.Context(), client, opts, out)
+}
+
+func init() {
+	for _, c := range []*cobra.Command{todayCmd, upcomingCmd, overdueCmd} {
+		rootCmd.AddCommand(c)
+		c.Long = c.Short + ".\n\n" + agendaLong
+		c.Flags().Duration("capacity", 8*time.Hour, "Planned time that fits in a day, 0 to never flag days as overbooked")
+	}
+	upcomingCmd.Flags().Int("days", 7, "Number of days to show, starting today")
+}
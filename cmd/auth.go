@@ -4,7 +4,15 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/mdjarv/todoist-cli/internal/auth"
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
 	"github.com/spf13/cobra"
 )
 
@@ -12,21 +20,225 @@ import (
 var authCmd = &cobra.Command{
 	Use:   "auth",
 	Short: "Authenticate with Todoist",
+	Long: `Authenticate with Todoist using OAuth, or with a personal API token.
+
+With --token the token is read from stdin, or prompted for without echo when
+stdin is a terminal. The token can be found under Settings > Integrations >
+Developer in Todoist. Setting TODOIST_API_TOKEN skips stored credentials
+entirely.
+
+On remote machines use --no-browser: open the printed URL on any machine,
+approve access, then paste the URL the browser was redirected to (or just its
+code parameter) back into the terminal.
+
+OAuth logins request the scopes given with --scope, or the scopes setting
+(default data:read_write,data:delete). Use data:read for a read-only token or
+task:add for one that can only add tasks. Granted scopes are stored with the
+credentials and commands needing other scopes are refused. Personal tokens
+always have full access.
+
+Credentials are stored per profile, use --profile to log in to another
+account and 'todoist auth switch' to change the default. With --encrypt, the
+encrypt_credentials setting or TODOIST_PASSPHRASE set, the token is encrypted
+with a passphrase before it is written, see 'todoist auth encrypt'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useToken, _ := cmd.Flags().GetBool("token")
+		encrypt, _ := cmd.Flags().GetBool("encrypt")
+		if useToken {
+			token, err := auth.ReadToken(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read token: %w", err)
+			}
+			return auth.LoginWithToken(cmd.Context(), token, encrypt)
+		}
+		port, _ := cmd.Flags().GetInt("port")
+		noBrowser, _ := cmd.Flags().GetBool("no-browser")
+		scope, _ := cmd.Flags().GetString("scope")
+		return auth.Login(auth.LoginOptions{Port: port, NoBrowser: noBrowser, In: os.Stdin, Scope: scope, Encrypt: encrypt})
+	},
+}
+
+// authLogoutCmd represents the auth logout command
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Revoke the access token and delete local credentials and cache",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		localOnly, _ := cmd.Flags().GetBool("local-only")
+		if err := auth.Logout(cmd.Context(), localOnly); err != nil {
+			return err
+		}
+		if err := cache.Clear(); err != nil {
+			return fmt.Errorf("failed to delete cache: %w", err)
+		}
+
+		if localOnly {
+			fmt.Printf("Deleted local credentials for profile %q, the token was not revoked\n", config.Profile())
+		} else {
+			fmt.Printf("Logged out of profile %q\n", config.Profile())
+		}
+		if os.Getenv(auth.TokenEnv) != "" {
+			fmt.Fprintf(os.Stderr, "warning: %s is still set and will keep being used\n", auth.TokenEnv)
+		}
+		return nil
+	},
+}
+
+// authStatusCmd represents the auth status command
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether a token is present and valid",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+		status := auth.CheckStatus(cmd.Context())
+		if out.Structured() {
+			return statusTable.WriteOne(out, newStatusRow(status))
+		}
+
+		fmt.Printf("Profile: %s\n", status.Profile)
+		if status.Source == "" {
+			fmt.Println("Token:   none, authenticate with: todoist auth")
+			return nil
+		}
+		fmt.Printf("Token:   present (%s)\n", status.Source)
+		if status.Scopes != nil {
+			fmt.Printf("Scopes:  %s\n", strings.Join(status.Scopes, ","))
+		} else if status.Err == nil {
+			fmt.Println("Scopes:  all (personal token)")
+		}
+
+		switch {
+		case status.User != nil:
+			fmt.Printf("Status:  valid, logged in as %s <%s>\n", status.User.FullName, status.User.Email)
+		case todoist.IsNetworkError(status.Err):
+			fmt.Println("Status:  unknown, Todoist could not be reached")
+		default:
+			fmt.Printf("Status:  invalid (%v)\n", status.Err)
+		}
+		return nil
+	},
+}
+
+// statusRow is 'auth status' in structured output formats
+type statusRow struct {
+	Profile string   `json:"profile" yaml:"profile"`
+	Source  string   `json:"source" yaml:"source"`
+	Scopes  []string `json:"scopes" yaml:"scopes"`
+	// State is none, valid, unknown when Todoist could not be reached, or invalid
+	State string `json:"state" yaml:"state"`
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	Email string `json:"email,omitempty" yaml:"email,omitempty"`
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func newStatusRow(status auth.Status) statusRow {
+	row := statusRow{Profile: status.Profile, Source: status.Source, Scopes: status.Scopes}
+	switch {
+	case status.Source == "":
+		row.State = "none"
+	case status.User != nil:
+		row.State = "valid"
+		row.Name, row.Email = status.User.FullName, status.User.Email
+	case todoist.IsNetworkError(status.Err):
+		row.State = "unknown"
+	default:
+		row.State = "invalid"
+	}
+	if status.Err != nil {
+		row.Error = status.Err.Error()
+	}
+	return row
+}
+
+var statusTable = output.Table[statusRow]{
+	Default: []string{"profile", "source", "scopes", "state", "name", "email", "error"},
+	Columns: []output.Column[statusRow]{
+		{Name: "profile", Header: "PROFILE", Value: func(r statusRow) string { return r.Profile }},
+		{Name: "source", Header: "SOURCE", Value: func(r statusRow) string { return r.Source }},
+		{Name: "scopes", Header: "SCOPES", Value: func(r statusRow) string { return strings.Join(r.Scopes, ",") }},
+		{Name: "state", Header: "STATE", Value: func(r statusRow) string { return r.State }},
+		{Name: "name", Header: "NAME", Value: func(r statusRow) string { return r.Name }},
+		{Name: "email", Header: "EMAIL", Value: func(r statusRow) string { return r.Email }},
+		{Name: "error", Header: "ERROR", Value: func(r statusRow) string { return r.Error }},
+	},
+}
+
+// authMigrateCmd represents the auth migrate command
+var authMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Exchange a personal API token for an OAuth token",
+	Long: `Exchange a personal API token for an OAuth token of the app configured with
+TODOIST_CLIENT_ID and TODOIST_CLIENT_SECRET, and store it in the profile.
+
+The personal token is read from stdin, or prompted for without echo when stdin
+is a terminal. With --stored the token already saved in the profile, or
+TODOIST_API_TOKEN, is migrated instead.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return auth.Login()
+		stored, _ := cmd.Flags().GetBool("stored")
+		scope, _ := cmd.Flags().GetString("scope")
+		encrypt, _ := cmd.Flags().GetBool("encrypt")
+
+		var token string
+		if stored {
+			creds, err := auth.LoadCredentials()
+			if err != nil {
+				return fmt.Errorf("failed to load credentials: %w", err)
+			}
+			token = creds.AccessToken
+		} else {
+			var err error
+			token, err = auth.ReadToken(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("failed to read token: %w", err)
+			}
+		}
+
+		return auth.MigrateToken(cmd.Context(), token, scope, encrypt)
+	},
+}
+
+// authEncryptCmd represents the auth encrypt command
+var authEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the stored credentials with a passphrase",
+	Long: `Encrypt the active profile's plaintext credentials with a passphrase.
+
+The key is derived from the passphrase with scrypt and the token is sealed
+with AES-GCM. Afterwards the passphrase is prompted for whenever the token is
+needed, or read from TODOIST_PASSPHRASE. Logging in again keeps the
+credentials encrypted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := auth.EncryptStoredCredentials(); err != nil {
+			return err
+		}
+		fmt.Printf("Encrypted credentials for profile %q\n", config.Profile())
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(authCmd)
 
-	// Here you will define your flags and configuration settings.
+	authCmd.Flags().Bool("token", false, "Authenticate with a personal API token read from stdin")
+	authCmd.Flags().Bool("no-browser", false, "Paste the redirect URL or code instead of waiting for a local callback, for SSH sessions")
+	authCmd.Flags().Int("port", auth.CallbackPort, "Local port for the OAuth redirect, must match the app's redirect URL")
+	authCmd.Flags().String("scope", "", "Comma separated OAuth scopes to request (default the scopes setting)")
+	authCmd.Flags().Bool("encrypt", false, "Store the token encrypted with a passphrase, never in plaintext")
+
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authMigrateCmd)
+	authCmd.AddCommand(authEncryptCmd)
 
-	// Cobra supports Persistent Flags which will work for this command
-	// and all subcommands, e.g.:
-	// authCmd.PersistentFlags().String("foo", "", "A help for foo")
+	authLogoutCmd.Flags().Bool("local-only", false, "Only delete local credentials, without revoking the token")
 
-	// Cobra supports local flags which will only run when this command
-	// is called directly, e.g.:
-	// authCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	authMigrateCmd.Flags().Bool("stored", false, "Migrate the token already saved in the profile")
+	authMigrateCmd.Flags().String("scope", "", "Comma separated OAuth scopes to request (default the scopes setting)")
+	authMigrateCmd.Flags().Bool("encrypt", false, "Store the token encrypted with a passphrase, never in plaintext")
 }
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+)
+
+// calendarCmd represents the calendar command
+var calendarCmd = &cobra.Command{
+	Use:   "calendar",
+	Short: "Show tasks in calendar apps",
+}
+
+// calendarServeCmd represents the calendar serve command
+var calendarServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve iCalendar feeds of your tasks",
+	Long: `Serve iCalendar feeds of open tasks over HTTP, for calendar apps to subscribe
+to so tasks show up alongside meetings.
+
+Timed tasks are events lasting their duration, tasks due on a date are all-day
+events, and deadlines are separate all-day events. Recurring tasks show their
+next occurrence only.
+
+  /tasks.ics               All open tasks, filtered by the project, section,
+                           label, priority, due and search parameters
+  /projects/<name>.ics     The tasks of one project, by name or ID
+
+The feeds are built from the local cache, which is synced with Todoist every
+--refresh, or reread from disk with --offline. Open the server's address in a
+browser to list the feeds.`,
+	Example: `  todoist calendar serve
+  todoist calendar serve --addr 127.0.0.1:9000 --refresh 15m
+  curl 'http://127.0.0.1:8765/tasks.ics?label=errand&due=7d'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		opts := cli.CalendarOptions{}
+		opts.Addr, _ = flags.GetString("addr")
+		opts.Refresh, _ = flags.GetDuration("refresh")
+		opts.Offline, _ = flags.GetBool("offline")
+		if opts.Refresh < time.Minute {
+			opts.Refresh = time.Minute
+		}
+
+		client, journal := newClient(opts.Offline, todoist.ScopeDataRead)
+		defer reportConflicts(journal)
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return cli.ServeCalendar(ctx, client, opts)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(calendarCmd)
+	calendarCmd.AddCommand(calendarServeCmd)
+
+	calendarServeCmd.Flags().String("addr", "127.0.0.1:8765", "Address to listen on")
+	calendarServeCmd.Flags().Duration("refresh", 5*time.Minute, "How often to sync the cache with Todoist, at least 1m")
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mdjarv/todoist-cli/internal/auth"
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/queue"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// newClient loads the stored credentials and returns a client that queues mutations while Todoist is unreachable,
+// or without sending them when offline is set. It exits when the token lacks any of the required scopes,
+// and the client refuses calls outside the granted ones.
+func newClient(offline bool, required ...string) (todoist.Client, *queue.Journal) {
+	creds, err := auth.LoadCredentials()
+	if errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(os.Stderr, "failed to load credentials, please authenticate first")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load credentials:", err)
+		os.Exit(1)
+	}
+	if missing := todoist.MissingScopes(creds.Scopes, required...); len(missing) > 0 {
+		fmt.Fprintln(os.Stderr, (&todoist.ScopeError{Granted: creds.Scopes, Missing: missing}).Error())
+		os.Exit(1)
+	}
+
+	journal, err := queue.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load offline queue:", err)
+		os.Exit(1)
+	}
+
+	readOnly, err := isReadOnly()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to load config:", err)
+		os.Exit(1)
+	}
+
+	// Read-only clients skip the queue, which would replay pending writes alongside reads
+	client := todoist.NewClient(creds.AccessToken)
+	if readOnly {
+		client = todoist.NewReadOnlyClient(client)
+	} else {
+		client = queue.NewClient(client, journal, offline)
+	}
+	return todoist.NewScopedClient(client, creds.Scopes), journal
+}
+
+// readOnlyFlag is bound to the persistent --read-only flag
+var readOnlyFlag bool
+
+// isReadOnly reports whether writes are disabled with --read-only or the read_only setting
+func isReadOnly() (bool, error) {
+	if readOnlyFlag {
+		return true, nil
+	}
+	settings, err := config.Current()
+	if err != nil {
+		return false, err
+	}
+	return settings.IsReadOnly(), nil
+}
+
+// reportConflicts prints queued changes that Todoist rejected when they were replayed
+func reportConflicts(journal *queue.Journal) {
+	conflicts, err := journal.TakeConflicts()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to update offline queue:", err)
+	}
+	for _, c := range conflicts {
+		fmt.Fprintf(os.Stderr, "conflict: queued %s from %s was rejected: %s\n", c.Mutation.Type, c.Mutation.QueuedAt.Format("2006-01-02 15:04"), c.Error)
+	}
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/auth"
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+)
+
+// completionSyncTimeout bounds the one sync made when there is no cache yet, so tab completion never hangs
+const completionSyncTimeout = 2 * time.Second
+
+type completeFunc = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// completionCache loads the cache of the profile given on the command line.
+// Only an empty cache is synced, with a short timeout, otherwise completion never touches the network.
+// Encrypted credentials are only used with TODOIST_PASSPHRASE set, otherwise the sync is skipped.
+func completionCache(cmd *cobra.Command) *cache.Cache {
+	// Completion skips PersistentPreRunE, so --profile has to be applied here
+	if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+		if err := config.SetProfile(profile); err != nil {
+			return nil
+		}
+	}
+
+	store, err := cache.Load()
+	if err != nil {
+		return nil
+	}
+	if offline, _ := cmd.Flags().GetBool("offline"); !store.Empty() || offline {
+		return store
+	}
+
+	// Never prompt for the passphrase of encrypted credentials, the shell hides the prompt
+	creds, err := auth.LoadCredentialsNoPrompt()
+	if err != nil {
+		return store
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionSyncTimeout)
+	defer cancel()
+	client := todoist.NewScopedClient(todoist.NewReadOnlyClient(todoist.NewClient(creds.AccessToken)), creds.Scopes)
+	store.Refresh(ctx, client)
+	return store
+}
+
+// completeProjects suggests project names, with the parent project as description
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store := completionCache(cmd)
+	if store == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := store.Names()
+
+	var suggestions []string
+	for _, p := range store.Projects {
+		suggestions = append(suggestions, completion(p.Name, names.Projects[p.ParentID]))
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSections suggests section names, only those of the --project project if it is set
+func completeSections(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store := completionCache(cmd)
+	if store == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names := store.Names()
+
+	projectID := ""
+	if project, _ := cmd.Flags().GetString("project"); project != "" {
+		if p, err := store.FindProject(project); err == nil {
+			projectID = p.ID
+		}
+	}
+
+	var suggestions []string
+	for _, s := range store.Sections {
+		if projectID == "" || s.ProjectID == projectID {
+			suggestions = append(suggestions, completion(s.Name, names.Projects[s.ProjectID]))
+		}
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLabels suggests personal labels and labels found on tasks, with how many open tasks use them
+func completeLabels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store := completionCache(cmd)
+	if store == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var labels []string
+	used := make(map[string]int)
+	for _, l := range store.Labels {
+		labels = append(labels, l.Name)
+	}
+	for _, t := range store.Tasks {
+		for _, l := range t.Labels {
+			if !t.Checked {
+				used[l]++
+			}
+			if !slices.Contains(labels, l) {
+				labels = append(labels, l)
+			}
+		}
+	}
+
+	var suggestions []string
+	for _, l := range labels {
+		description := fmt.Sprintf("%d open tasks", used[l])
+		if used[l] == 1 {
+			description = "1 open task"
+		}
+		suggestions = append(suggestions, completion(l, description))
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTasks suggests the IDs of cached tasks with their content as description, skipping tasks already given.
+// The cache only holds open tasks, so completed ones are never offered.
+func completeTasks(maxArgs int) completeFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if maxArgs > 0 && len(args) >= maxArgs {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		store := completionCache(cmd)
+		if store == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		var suggestions []string
+		for _, t := range store.Tasks {
+			if !slices.Contains(args, t.ID) {
+				suggestions = append(suggestions, completion(t.ID, t.Content))
+			}
+		}
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeProfiles suggests the profiles that have credentials
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	profiles, _ := config.Profiles()
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSettingKeys suggests setting names with their help, for the first argument only
+func completeSettingKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var suggestions []string
+	for _, k := range config.Keys {
+		suggestions = append(suggestions, completion(k.Name, k.Help))
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeList suggests a fixed set of values
+func completeList(values ...string) completeFunc {
+	return cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp)
+}
+
+// completeCommaList suggests values for a comma separated flag, completing the last element
+func completeCommaList(values ...string) completeFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		prefix := ""
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			prefix = toComplete[:i+1]
+		}
+		var suggestions []string
+		for _, v := range values {
+			suggestions = append(suggestions, prefix+v)
+		}
+		return suggestions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// completion formats a suggestion with a description shown by shells that support them
+func completion(value, description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if description == "" {
+		return value
+	}
+	return value + "\t" + description
+}
+
+var priorityValues = []string{"p1\turgent", "p2\thigh", "p3\tmedium", "p4\tnormal"}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and change settings",
+	Long: `Read and change settings stored in config.yaml in the config directory
+($XDG_CONFIG_HOME/todoist, or ~/.config/todoist).
+
+Settings are resolved in order: command line flags, environment variables,
+the active profile's section of the config file, the top level of the config
+file, and finally built-in defaults.`,
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:               "get <key>",
+	Short:             "Print the effective value of a setting",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSettingKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := config.LookupKey(args[0])
+		if err != nil {
+			return err
+		}
+		file, err := config.LoadFile()
+		if err != nil {
+			return err
+		}
+		value, _ := file.Resolve(key)
+		fmt.Println(value)
+		return nil
+	},
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:               "set <key> <value>",
+	Short:             "Store a setting in the config file, an empty value unsets it",
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeSettingKeys,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := config.LookupKey(args[0])
+		if err != nil {
+			return err
+		}
+		if err := key.Validate(args[1]); err != nil {
+			return err
+		}
+		file, err := config.LoadFile()
+		if err != nil {
+			return err
+		}
+
+		forProfile, _ := cmd.Flags().GetBool("this-profile")
+		file.Set(key, args[1], forProfile)
+		return file.Save()
+	},
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings with their effective values and sources",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, err := config.LoadFile()
+		if err != nil {
+			return err
+		}
+
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		var rows []settingRow
+		for _, key := range config.Keys {
+			value, source := file.Resolve(key)
+			if key.Name == "client_secret" && value != "" {
+				value = "********"
+			}
+			if source == config.SourceEnv {
+				source += " (" + key.Env + ")"
+			}
+			rows = append(rows, settingRow{Key: key.Name, Value: value, Source: source})
+		}
+		return settingTable.Write(out, rows)
+	},
+}
+
+// settingRow is one line of 'config list'
+type settingRow struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value" yaml:"value"`
+	Source string `json:"source" yaml:"source"`
+}
+
+var settingTable = output.Table[settingRow]{
+	Default: []string{"key", "value", "source"},
+	Columns: []output.Column[settingRow]{
+		{Name: "key", Header: "KEY", Value: func(r settingRow) string { return r.Key }},
+		{Name: "value", Header: "VALUE", Value: func(r settingRow) string { return r.Value }},
+		{Name: "source", Header: "SOURCE", Value: func(r settingRow) string { return r.Source }},
+	},
+}
+
+// configEditCmd represents the config edit command
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in $EDITOR",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := config.Path(config.FileName)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			file := &config.File{}
+			if err := file.Save(); err != nil {
+				return err
+			}
+		}
+
+		if err := cli.OpenEditor(path); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if _, err := config.ParseFile(data); err != nil {
+			return fmt.Errorf("%w, fix it with: todoist config edit", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+
+	configSetCmd.Flags().Bool("this-profile", false, "Store the setting for the active profile only")
+}
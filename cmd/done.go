@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// doneCmd represents the done command
+var doneCmd = &cobra.Command{
+	Use:   "done [id|query]...",
+	Short: "Complete tasks",
+	Long: `Complete tasks given by ID or by a query fuzzy-matched against task content.
+
+Without arguments, IDs are read from stdin one per line; only the first field
+of each line is used, so the output of 'todoist list' can be piped in. A
+query matching one task asks for confirmation and a query matching several
+asks which to complete. Use --yes to act on single matches without asking,
+ambiguous queries are always refused then.
+
+With --output json, or any other structured format or --columns, the completed
+tasks are written with whether each change is queued.`,
+	Example: `  todoist done 6X7rM8997g3RQmvh
+  todoist done "buy milk" --yes
+  todoist list | grep groceries | todoist done`,
+	ValidArgsFunction: completeTasks(0),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+		args, opts, err := selectArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		client, journal := newClient(opts.Cache.Offline, todoist.ScopeDataReadWrite)
+		defer reportConflicts(journal)
+		return cli.Done(cmd.Context(), client, args, opts, out)
+	},
+}
+
+// reopenCmd represents the reopen command
+var reopenCmd = &cobra.Command{
+	Use:   "reopen [id|query]...",
+	Short: "Reopen completed tasks",
+	Long: `Reopen completed tasks given by ID or by a query fuzzy-matched against tasks
+completed in the last four weeks.
+
+Arguments, stdin, prompts and output work as for 'todoist done'. With
+--offline only IDs can be used.`,
+	// Completed tasks are not cached, so there is nothing to suggest without asking Todoist
+	ValidArgsFunction: cobra.NoFileCompletions,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+		args, opts, err := selectArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		client, journal := newClient(opts.Cache.Offline, todoist.ScopeDataReadWrite)
+		defer reportConflicts(journal)
+		return cli.Reopen(cmd.Context(), client, args, opts, out)
+	},
+}
+
+// selectArgs returns the task arguments, read from stdin when none are given, and how to prompt for matches
+func selectArgs(cmd *cobra.Command, args []string) ([]string, cli.SelectOptions, error) {
+	yes, _ := cmd.Flags().GetBool("yes")
+	offline, _ := cmd.Flags().GetBool("offline")
+	opts := cli.SelectOptions{Yes: yes, Cache: cache.Options{MaxAge: time.Minute, Offline: offline}}
+
+	stdinIsTerminal := term.IsTerminal(int(os.Stdin.Fd()))
+	if len(args) == 0 {
+		if stdinIsTerminal {
+			return nil, opts, fmt.Errorf("no tasks given, pass IDs or queries as arguments or on stdin")
+		}
+		var err error
+		if args, err = readIDs(os.Stdin); err != nil {
+			return nil, opts, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		if len(args) == 0 {
+			return nil, opts, fmt.Errorf("no task IDs on stdin")
+		}
+	}
+
+	// Prompts need a terminal, which is the controlling tty when stdin is a pipe
+	if stdinIsTerminal {
+		opts.Prompt = bufio.NewReader(os.Stdin)
+	} else if tty, err := os.Open("/dev/tty"); err == nil {
+		opts.Prompt = bufio.NewReader(tty)
+	}
+	return args, opts, nil
+}
+
+// readIDs returns the first field of every line, skipping blank lines and the ID header of 'todoist list'
+func readIDs(f *os.File) ([]string, error) {
+	var ids []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "ID" {
+			continue
+		}
+		ids = append(ids, fields[0])
+	}
+	return ids, scanner.Err()
+}
+
+func init() {
+	rootCmd.AddCommand(doneCmd)
+	rootCmd.AddCommand(reopenCmd)
+
+	doneCmd.Flags().BoolP("yes", "y", false, "Act on single fuzzy matches without asking")
+	reopenCmd.Flags().BoolP("yes", "y", false, "Act on single fuzzy matches without asking")
+}
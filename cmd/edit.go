@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+)
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edit a task in $EDITOR",
+	Long: `Open a task in $VISUAL or $EDITOR as a document with YAML front matter
+holding the project, section, labels, priority, due date, deadline and
+duration, followed by the task content on the first line and the description
+below it.
+
+Only the fields that changed are sent. If the document is invalid the editor
+is opened again with the error at the top. Save an empty file to abort.
+
+With --output json, or any other structured format or --columns, the task is
+written with whether the change is queued.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTasks(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		offline, _ := cmd.Flags().GetBool("offline")
+		client, journal := newClient(offline, todoist.ScopeDataReadWrite)
+		defer reportConflicts(journal)
+
+		return cli.Edit(cmd.Context(), client, args[0], cache.Options{MaxAge: time.Minute, Offline: offline}, out)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export tasks as Markdown, todo.txt, Org or iCalendar",
+	Long: `Write open tasks to stdout in a format for documents, other task managers or
+calendars:
+
+  markdown  Checklists under a heading per project and section, subtasks nested
+            below their parents, with priority, dates, duration and labels.
+  todotxt   One line per task with (A) to (C) priorities, +project, @labels
+            and due: dates. id: and p: tags keep subtasks, and the file can be
+            read back with 'todoist import'.
+  org       TODO headings per project and section, SCHEDULED at the due date,
+            with the DEADLINE, labels as tags and the duration as Effort.
+  ics       An iCalendar file. Tasks with a duration become events, the others
+            to-dos due at their due date or deadline.
+
+Filters work as in 'todoist list'. Recurring tasks are exported with their
+next occurrence only.`,
+	Example: `  todoist export --format markdown --project Work > work.md
+  todoist export --format ics --due 7d > week.ics`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		opts := cli.ExportOptions{}
+		opts.Format, _ = flags.GetString("format")
+		if err := cli.ValidateExportFormat(opts.Format); err != nil {
+			return err
+		}
+		opts.Project, _ = flags.GetString("project")
+		opts.Section, _ = flags.GetString("section")
+		opts.Filter.Labels, _ = flags.GetStringArray("label")
+		opts.Filter.Due, _ = flags.GetString("due")
+
+		var err error
+		if priority, _ := flags.GetString("priority"); priority != "" {
+			if opts.Filter.Priority, err = cli.ParsePriority(priority); err != nil {
+				return err
+			}
+		}
+		if opts.Filter.Due != "" {
+			if err := query.ValidateDue(opts.Filter.Due); err != nil {
+				return err
+			}
+		}
+
+		offline, _ := flags.GetBool("offline")
+		client, journal := newClient(offline, todoist.ScopeDataRead)
+		defer reportConflicts(journal)
+
+		opts.Cache = cache.Options{MaxAge: 5 * time.Minute, Offline: offline}
+		return cli.Export(cmd.Context(), client, opts, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringP("format", "f", cli.ExportMarkdown, "Format to write: markdown, todotxt, org or ics")
+	exportCmd.Flags().StringP("project", "p", "", "Only tasks in this project, by name or ID")
+	exportCmd.Flags().StringP("section", "s", "", "Only tasks in this section, by name or ID")
+	exportCmd.Flags().StringArrayP("label", "l", nil, "Only tasks with this label, repeat to require several")
+	exportCmd.Flags().String("priority", "", "Only tasks with this priority, p1 to p4")
+	exportCmd.Flags().StringP("due", "d", "", "Only tasks due today, tomorrow, overdue or within a number of days like 7d")
+
+	exportCmd.RegisterFlagCompletionFunc("format", completeList(cli.ExportFormats...))
+	exportCmd.RegisterFlagCompletionFunc("project", completeProjects)
+	exportCmd.RegisterFlagCompletionFunc("section", completeSections)
+	exportCmd.RegisterFlagCompletionFunc("label", completeLabels)
+	exportCmd.RegisterFlagCompletionFunc("priority", completeList(priorityValues...))
+	exportCmd.RegisterFlagCompletionFunc("due", completeList("today", "tomorrow", "overdue", "7d"))
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import tasks from CSV, todo.txt or Taskwarrior",
+	Long: `Import tasks from a file, or stdin with -, sending them to Todoist in batches
+of up to 100 per request.
+
+The format is taken from the file extension (.csv, .txt or .json) unless
+--format is given:
+
+  csv          A header row names the columns: content, description, project,
+               section, labels (comma separated), priority (p1 to p4), due,
+               deadline, duration, and id with parent, or indent as in
+               Todoist's CSV template, for subtasks.
+  todotxt      (A) to (D) set the priority, +project the project, @context a
+               label, due:YYYY-MM-DD the due date, id: and p: link subtasks.
+  taskwarrior  The output of 'task export'. Dotted projects become nested
+               projects, tags labels and annotations the description.
+
+Subtasks stay below their parents and go to their parent's project. Tasks
+without a project go to --project, the default_project setting or the Inbox.
+Completed and deleted tasks are skipped. Projects, sections and labels that do
+not exist are an error unless --create-missing is given.
+
+With --dry-run nothing is changed: the tasks are listed, in any --output
+format, along with what would be created.`,
+	Example: `  todoist import tasks.csv --dry-run
+  todoist import todo.txt --project Imported --create-missing
+  task export | todoist import --format taskwarrior -`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := config.Current()
+		if err != nil {
+			return err
+		}
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		path := args[0]
+		format, _ := flags.GetString("format")
+		if format == "" {
+			if format, err = cli.ImportFormat(path); err != nil {
+				return err
+			}
+		}
+
+		var r io.Reader = os.Stdin
+		if path != "-" {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		tasks, skipped, err := cli.ParseImport(r, format)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if skipped > 0 {
+			fmt.Fprintf(os.Stderr, "Skipped %d completed or deleted tasks\n", skipped)
+		}
+		if len(tasks) == 0 {
+			return fmt.Errorf("no tasks to import in %s", path)
+		}
+
+		opts := cli.ImportOptions{}
+		opts.Project, _ = flags.GetString("project")
+		if opts.Project == "" {
+			opts.Project = settings.DefaultProject
+		}
+		opts.CreateMissing, _ = flags.GetBool("create-missing")
+		opts.DryRun, _ = flags.GetBool("dry-run")
+
+		required := []string{todoist.ScopeDataRead}
+		if !opts.DryRun {
+			required = append(required, todoist.ScopeTaskAdd)
+			if opts.CreateMissing {
+				required = append(required, todoist.ScopeDataReadWrite)
+			}
+		}
+		offline, _ := flags.GetBool("offline")
+		client, journal := newClient(offline, required...)
+		defer reportConflicts(journal)
+
+		opts.Cache = cache.Options{MaxAge: time.Minute, Offline: offline}
+		return cli.Import(cmd.Context(), client, tasks, opts, out)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringP("format", "f", "", "Format of the file: csv, todotxt or taskwarrior (default from the extension)")
+	importCmd.Flags().StringP("project", "p", "", "Project name or ID for tasks without one (default the default_project setting, or the Inbox)")
+	importCmd.Flags().Bool("create-missing", false, "Create projects, sections and labels that do not exist yet")
+	importCmd.Flags().Bool("dry-run", false, "List the tasks and what would be created without changing anything")
+
+	importCmd.RegisterFlagCompletionFunc("format", completeList(cli.ImportFormats...))
+	importCmd.RegisterFlagCompletionFunc("project", completeProjects)
+}
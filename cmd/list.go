@@ -1,11 +1,11 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
+	"time"
 
-	"github.com/mdjarv/todoist-cli/internal/auth"
+	"github.com/mdjarv/todoist-cli/internal/cache"
 	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/query"
 	"github.com/mdjarv/todoist-cli/internal/todoist"
 	"github.com/spf13/cobra"
 )
@@ -14,24 +14,89 @@ import (
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List tasks",
+	Long: `List tasks from the local cache, syncing with Todoist when it is stale.
+
+Filters are combined, so every given filter must match. --due accepts today,
+tomorrow, overdue or a number of days ahead like 7d. --sort takes a comma
+separated list of due, priority, project, created, order and day, prefix a field
+with - to reverse it, e.g. --sort due,-priority.
+
+With --tree, subtasks are listed below their parents in child order, and
+parents show how many of their subtasks are done, counting those completed in
+the last four weeks. Offline only the open subtasks are counted.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		creds, err := auth.LoadCredentials()
+		out, err := outputOptions(cmd)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "failed to load credentials, please authenticate first")
-			os.Exit(1)
+			return err
+		}
+
+		flags := cmd.Flags()
+		opts := cli.ListOptions{}
+		opts.Project, _ = flags.GetString("project")
+		opts.Section, _ = flags.GetString("section")
+		opts.AssignedTo, _ = flags.GetString("assigned-to")
+		opts.Filter.ParentID, _ = flags.GetString("parent")
+		opts.Filter.Labels, _ = flags.GetStringArray("label")
+		opts.Filter.Due, _ = flags.GetString("due")
+		opts.Filter.NoDue, _ = flags.GetBool("no-due")
+		opts.Filter.Search, _ = flags.GetString("search")
+		opts.Limit, _ = flags.GetInt("limit")
+		opts.Tree, _ = flags.GetBool("tree")
+
+		if priority, _ := flags.GetString("priority"); priority != "" {
+			if opts.Filter.Priority, err = cli.ParsePriority(priority); err != nil {
+				return err
+			}
 		}
+		if opts.Filter.Due != "" {
+			if err := query.ValidateDue(opts.Filter.Due); err != nil {
+				return err
+			}
+		}
+		sort, _ := flags.GetString("sort")
+		if opts.Sort, err = query.ParseSort(sort); err != nil {
+			return err
+		}
+
+		offline, _ := flags.GetBool("offline")
+		client, journal := newClient(offline, todoist.ScopeDataRead)
+		defer reportConflicts(journal)
 
-			jsonOut, _ := cmd.Flags().GetBool("json")
-			client := todoist.NewClient(creds.AccessToken)
-			return cli.List(cmd.Context(), client, jsonOut)
+		maxAge, _ := flags.GetDuration("max-age")
+		opts.Cache = cache.Options{MaxAge: maxAge, Offline: offline}
+		return cli.List(cmd.Context(), client, opts, out)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
 
-	// Add --json flag for outputting tasks as JSON
 	listCmd.Flags().Bool("json", false, "Output tasks as JSON")
+	listCmd.Flags().MarkDeprecated("json", "use --output json instead")
+	listCmd.Flags().Duration("max-age", 5*time.Minute, "Serve cached tasks younger than this without syncing")
+
+	listCmd.Flags().StringP("project", "p", "", "Only tasks in this project, by name or ID")
+	listCmd.Flags().StringP("section", "s", "", "Only tasks in this section, by name or ID")
+	listCmd.Flags().StringArrayP("label", "l", nil, "Only tasks with this label, repeat to require several")
+	listCmd.Flags().String("priority", "", "Only tasks with this priority, p1 to p4")
+	listCmd.Flags().StringP("due", "d", "", "Only tasks due today, tomorrow, overdue or within a number of days like 7d")
+	listCmd.Flags().Bool("no-due", false, "Only tasks without a due date")
+	listCmd.Flags().String("assigned-to", "", "Only tasks assigned to this user ID, or me")
+	listCmd.Flags().String("parent", "", "Only subtasks of this task ID")
+	listCmd.Flags().String("search", "", "Only tasks whose content or description contains this text")
+	listCmd.Flags().String("sort", "", "Sort by comma separated fields: due, priority, project, created, order, day; prefix with - to reverse")
+	listCmd.Flags().Int("limit", 0, "Show at most this many tasks, 0 for all")
+	listCmd.Flags().Bool("tree", false, "Show subtasks indented below their parents, hiding those of collapsed tasks")
+	listCmd.MarkFlagsMutuallyExclusive("due", "no-due")
+
+	listCmd.RegisterFlagCompletionFunc("project", completeProjects)
+	listCmd.RegisterFlagCompletionFunc("section", completeSections)
+	listCmd.RegisterFlagCompletionFunc("label", completeLabels)
+	listCmd.RegisterFlagCompletionFunc("priority", completeList(priorityValues...))
+	listCmd.RegisterFlagCompletionFunc("due", completeList("today", "tomorrow", "overdue", "7d"))
+	listCmd.RegisterFlagCompletionFunc("assigned-to", completeList("me"))
+	listCmd.RegisterFlagCompletionFunc("parent", completeTasks(0))
+	listCmd.RegisterFlagCompletionFunc("sort", completeCommaList(query.SortFields...))
 
 	// Here you will define your flags and configuration settings.
 
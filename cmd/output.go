@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// outputOptions reads the global output flags, falling back to the output setting
+func outputOptions(cmd *cobra.Command) (output.Options, error) {
+	settings, err := config.Current()
+	if err != nil {
+		return output.Options{}, err
+	}
+
+	flags := cmd.Flags()
+	opts := output.Options{Format: settings.Output, DateFormat: settings.DateFormat}
+	if flags.Changed("output") {
+		opts.Format, _ = flags.GetString("output")
+	}
+	// --json predates --output and is kept on the commands that had it
+	if flags.Lookup("json") != nil && flags.Changed("json") {
+		if jsonOut, _ := flags.GetBool("json"); jsonOut {
+			opts.Format = output.FormatJSON
+		}
+	}
+	if err := output.ValidateFormat(opts.Format); err != nil {
+		return output.Options{}, err
+	}
+
+	opts.Template, _ = flags.GetString("template")
+	opts.NoHeader, _ = flags.GetBool("no-header")
+	if columns, _ := flags.GetString("columns"); columns != "" {
+		for _, c := range strings.Split(columns, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				opts.Columns = append(opts.Columns, c)
+			}
+		}
+	}
+
+	// Tables on a terminal are fitted to its width and colored unless NO_COLOR is set
+	if fd := int(os.Stdout.Fd()); term.IsTerminal(fd) {
+		if width, _, err := term.GetSize(fd); err == nil {
+			opts.Width = width
+		}
+		opts.Color = os.Getenv("NO_COLOR") == ""
+	}
+	return opts, nil
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// authListCmd represents the auth list command
+var authListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List authenticated profiles",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles, err := config.Profiles()
+		if err != nil {
+			return fmt.Errorf("failed to list profiles: %w", err)
+		}
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+		if len(profiles) == 0 && !out.Structured() {
+			fmt.Println("No profiles found, authenticate with: todoist auth")
+			return nil
+		}
+
+		rows := make([]profileRow, len(profiles))
+		for i, name := range profiles {
+			rows[i] = profileRow{Name: name, Active: name == config.Profile()}
+		}
+		return profileTable.Write(out, rows)
+	},
+}
+
+// profileRow is one line of 'auth list'
+type profileRow struct {
+	Name   string `json:"name" yaml:"name"`
+	Active bool   `json:"active" yaml:"active"`
+}
+
+var profileTable = output.Table[profileRow]{
+	Default: []string{"active", "name"},
+	Columns: []output.Column[profileRow]{
+		{Name: "active", Header: " ", Value: func(r profileRow) string {
+			if r.Active {
+				return "*"
+			}
+			return " "
+		}},
+		{Name: "name", Header: "PROFILE", Value: func(r profileRow) string { return r.Name }},
+	},
+}
+
+// authSwitchCmd represents the auth switch command
+var authSwitchCmd = &cobra.Command{
+	Use:               "switch <profile>",
+	Short:             "Set the profile used by default",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfiles,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.SwitchProfile(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Switched to profile %q\n", args[0])
+		return nil
+	},
+}
+
+// authRemoveCmd represents the auth remove command
+var authRemoveCmd = &cobra.Command{
+	Use:               "remove <profile>",
+	Short:             "Delete a profile's credentials, cache and queued changes",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfiles,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.RemoveProfile(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Removed profile %q\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	authCmd.AddCommand(authListCmd)
+	authCmd.AddCommand(authSwitchCmd)
+	authCmd.AddCommand(authRemoveCmd)
+}
@@ -1,10 +1,10 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
-	"github.com/mdjarv/todoist-cli/internal/auth"
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/output"
 	"github.com/mdjarv/todoist-cli/internal/todoist"
 	"github.com/mdjarv/todoist-cli/internal/ui"
 	"github.com/spf13/cobra"
@@ -14,15 +14,24 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "todoist",
 	Short: "Todoist CLI",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+			return config.SetProfile(profile)
+		}
+		return config.ValidateProfile(config.Profile())
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		creds, err := auth.LoadCredentials()
+		settings, err := config.Current()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "failed to load credentials, please authenticate first")
-			os.Exit(1)
+			return err
 		}
-
-		client := todoist.NewClient(creds.AccessToken)
-		return ui.Run(client)
+		offline, _ := cmd.Flags().GetBool("offline")
+		client, journal := newClient(offline, todoist.ScopeDataRead)
+		readOnly, err := isReadOnly()
+		if err != nil {
+			return err
+		}
+		return ui.Run(client, journal, ui.Options{Offline: offline, ReadOnly: readOnly, Settings: settings})
 	},
 }
 
@@ -36,5 +45,13 @@ func Execute() {
 }
 
 func init() {
-	// rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().Bool("offline", false, "Only use the local cache and queue changes, never contact Todoist")
+	rootCmd.PersistentFlags().BoolVar(&readOnlyFlag, "read-only", false, "Refuse every change to Todoist data, for scripts that must only read")
+	rootCmd.PersistentFlags().StringP("output", "o", "", "Output format: table, json, ndjson, csv, tsv or yaml (default the output setting)")
+	rootCmd.PersistentFlags().Bool("no-header", false, "Omit the header row of table, csv and tsv output")
+	rootCmd.PersistentFlags().String("template", "", "Format each result with a Go text/template, e.g. '{{.ID}} {{.Content}}'")
+	rootCmd.PersistentFlags().String("columns", "", "Comma separated columns to show, e.g. id,content,due")
+	rootCmd.PersistentFlags().String("profile", "", "Account profile to use (default $TODOIST_PROFILE or the profile chosen with 'auth switch')")
+	rootCmd.RegisterFlagCompletionFunc("output", completeList(output.Formats...))
+	rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
 }
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+)
+
+// showCmd represents the show command
+var showCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a task in full",
+	Long: `Show a task with its project and section, labels, priority, dates,
+recurrence, creator and assignee, the description rendered from Markdown,
+its subtasks, including those completed in the last four weeks, and its
+comments with their attachments.
+
+With --json, or any other --output format, the same details are written as
+one document. Comments, names of people and completed subtasks are not
+available with --offline.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeTasks(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		offline, _ := cmd.Flags().GetBool("offline")
+		client, journal := newClient(offline, todoist.ScopeDataRead)
+		defer reportConflicts(journal)
+
+		return cli.Show(cmd.Context(), client, args[0], cache.Options{MaxAge: 5 * time.Minute, Offline: offline}, out)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().Bool("json", false, "Output the task details as one JSON document")
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show productivity stats, goals and karma",
+	Long: `Show the tasks completed on each of the last seven days and in each of the
+last weeks against the daily and weekly goals, the current and longest
+streaks of meeting them, karma with its trend, and the tasks completed per
+project over the last seven days.
+
+With --output json, or any other structured format, the stats are written as
+returned by Todoist. The stats are not cached, so --offline is refused.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		offline, _ := cmd.Flags().GetBool("offline")
+		client, journal := newClient(offline, todoist.ScopeDataRead)
+		defer reportConflicts(journal)
+
+		opts := cli.StatsOptions{Cache: cache.Options{MaxAge: time.Hour, Offline: offline}}
+		return cli.Stats(cmd.Context(), client, opts, out)
+	},
+}
+
+// statsGoalsCmd groups the goal commands
+var statsGoalsCmd = &cobra.Command{
+	Use:   "goals",
+	Short: "Manage karma goals",
+}
+
+// statsGoalsSetCmd represents the stats goals set command
+var statsGoalsSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Change the daily and weekly goals, days off and vacation mode",
+	Long: `Change the karma goals. Only the settings given as flags are changed.
+
+Days off don't break streaks; give them as names or numbers from 1 for Monday
+to 7 for Sunday, or none. Vacation mode keeps streaks while away.`,
+	Example: `  todoist stats goals set --daily 5 --weekly 25
+  todoist stats goals set --ignore-days sat,sun
+  todoist stats goals set --vacation`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		var options todoist.UpdateGoalsOptions
+		if flags.Changed("daily") {
+			daily, _ := flags.GetInt("daily")
+			if daily < 0 {
+				return fmt.Errorf("invalid daily goal %d", daily)
+			}
+			options.DailyGoal = &daily
+		}
+		if flags.Changed("weekly") {
+			weekly, _ := flags.GetInt("weekly")
+			if weekly < 0 {
+				return fmt.Errorf("invalid weekly goal %d", weekly)
+			}
+			options.WeeklyGoal = &weekly
+		}
+		if flags.Changed("ignore-days") {
+			value, _ := flags.GetString("ignore-days")
+			days, err := cli.ParseWeekdays(value)
+			if err != nil {
+				return err
+			}
+			options.IgnoreDays = &days
+		}
+		if flags.Changed("vacation") {
+			vacation, _ := flags.GetBool("vacation")
+			options.VacationMode = &vacation
+		}
+		if flags.Changed("karma") {
+			karma, _ := flags.GetBool("karma")
+			disabled := !karma
+			options.KarmaDisabled = &disabled
+		}
+
+		offline, _ := flags.GetBool("offline")
+		if offline {
+			return fmt.Errorf("changing goals needs Todoist, it cannot be queued offline")
+		}
+		client, journal := newClient(offline, todoist.ScopeDataReadWrite)
+		defer reportConflicts(journal)
+		return cli.SetGoals(cmd.Context(), client, options)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.AddCommand(statsGoalsCmd)
+	statsGoalsCmd.AddCommand(statsGoalsSetCmd)
+
+	flags := statsGoalsSetCmd.Flags()
+	flags.Int("daily", 0, "Tasks to complete each day, 0 for no goal")
+	flags.Int("weekly", 0, "Tasks to complete each week, 0 for no goal")
+	flags.String("ignore-days", "", "Days off that don't break streaks, like sat,sun, or none")
+	flags.Bool("vacation", false, "Turn vacation mode on, --vacation=false to turn it off")
+	flags.Bool("karma", true, "Turn karma on, --karma=false to turn it off")
+	statsGoalsSetCmd.RegisterFlagCompletionFunc("ignore-days", completeCommaList("mon", "tue", "wed", "thu", "fri", "sat", "sun", "none"))
+}
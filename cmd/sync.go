@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Synchronise local state with Todoist",
+}
+
+// syncPushCmd represents the sync push command
+var syncPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Send changes queued while offline",
+	Long: `Send the changes queued while offline to Todoist. Changes Todoist rejects are
+reported as conflicts and dropped from the queue.
+
+With --output json, or any other structured format, the counts of queued, sent,
+rejected and still queued changes are written.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		client, journal := newClient(false)
+		row := pushRow{Queued: journal.Len()}
+		if row.Queued == 0 {
+			if out.Structured() || len(out.Columns) > 0 {
+				return pushTable.WriteOne(out, row)
+			}
+			fmt.Println("No queued changes.")
+			return nil
+		}
+
+		result, err := journal.Push(cmd.Context(), client)
+		if err != nil {
+			return fmt.Errorf("failed to push queued changes: %w", err)
+		}
+		row.Sent, row.Conflicts, row.Remaining = result.Applied, len(result.Conflicts), journal.Len()
+
+		defer reportConflicts(journal)
+		if out.Structured() || len(out.Columns) > 0 {
+			return pushTable.WriteOne(out, row)
+		}
+		fmt.Printf("Sent %d of %d queued changes.\n", row.Sent, row.Queued)
+		return nil
+	},
+}
+
+// pushRow is the outcome of 'sync push' in structured output formats
+type pushRow struct {
+	// Queued is how many changes were queued before the push, Remaining how many still are
+	Queued    int `json:"queued" yaml:"queued"`
+	Sent      int `json:"sent" yaml:"sent"`
+	Conflicts int `json:"conflicts" yaml:"conflicts"`
+	Remaining int `json:"remaining" yaml:"remaining"`
+}
+
+var pushTable = output.Table[pushRow]{
+	Default: []string{"queued", "sent", "conflicts", "remaining"},
+	Columns: []output.Column[pushRow]{
+		{Name: "queued", Header: "QUEUED", Value: func(r pushRow) string { return strconv.Itoa(r.Queued) }},
+		{Name: "sent", Header: "SENT", Value: func(r pushRow) string { return strconv.Itoa(r.Sent) }},
+		{Name: "conflicts", Header: "CONFLICTS", Value: func(r pushRow) string { return strconv.Itoa(r.Conflicts) }},
+		{Name: "remaining", Header: "REMAINING", Value: func(r pushRow) string { return strconv.Itoa(r.Remaining) }},
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.AddCommand(syncPushCmd)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/cli"
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"github.com/spf13/cobra"
+)
+
+// minWatchInterval keeps polling within Todoist's rate limits
+const minWatchInterval = 5 * time.Second
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream task changes as events",
+	Long: `Poll Todoist with incremental syncs every --interval and print an event for
+every task that was added, updated, completed, uncompleted or deleted since the
+previous poll. Changes made before watch starts are not reported, and a task
+added and completed between two polls is reported as added, then completed.
+
+Events are lines of time, event, task ID, content and project. With --output
+json or ndjson each event is one JSON object per line holding type, time and
+the full task; csv, tsv and --template work too.
+
+--exec runs a shell command for every event, with the task as JSON on stdin and
+TODOIST_EVENT and TODOIST_TASK_ID in the environment. Its output goes to stderr.
+
+The filter flags work as for 'todoist list'; an event is reported when the
+task matches before or after the change.`,
+	Example: `  todoist watch
+  todoist watch -p Work --interval 1m -o ndjson
+  todoist watch -l errand --exec 'jq -r .content | notify-send "Todoist: $TODOIST_EVENT"'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := outputOptions(cmd)
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		opts := cli.WatchOptions{}
+		opts.Interval, _ = flags.GetDuration("interval")
+		opts.Hook, _ = flags.GetString("exec")
+		opts.Project, _ = flags.GetString("project")
+		opts.Section, _ = flags.GetString("section")
+		opts.Filter.Labels, _ = flags.GetStringArray("label")
+		opts.Filter.Due, _ = flags.GetString("due")
+		opts.Filter.Search, _ = flags.GetString("search")
+		if opts.Interval < minWatchInterval {
+			opts.Interval = minWatchInterval
+		}
+		if priority, _ := flags.GetString("priority"); priority != "" {
+			if opts.Filter.Priority, err = cli.ParsePriority(priority); err != nil {
+				return err
+			}
+		}
+		if opts.Filter.Due != "" {
+			if err := query.ValidateDue(opts.Filter.Due); err != nil {
+				return err
+			}
+		}
+
+		offline, _ := flags.GetBool("offline")
+		client, journal := newClient(offline, todoist.ScopeDataRead)
+		defer reportConflicts(journal)
+
+		opts.Cache = cache.Options{Offline: offline}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return cli.Watch(ctx, client, opts, out)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().Duration("interval", 30*time.Second, "How often to poll Todoist, at least 5s")
+	watchCmd.Flags().String("exec", "", "Shell command to run for every event, with the task as JSON on stdin")
+	watchCmd.Flags().StringP("project", "p", "", "Only tasks in this project, by name or ID")
+	watchCmd.Flags().StringP("section", "s", "", "Only tasks in this section, by name or ID")
+	watchCmd.Flags().StringArrayP("label", "l", nil, "Only tasks with this label, repeat to require several")
+	watchCmd.Flags().String("priority", "", "Only tasks with this priority, p1 to p4")
+	watchCmd.Flags().StringP("due", "d", "", "Only tasks due today, tomorrow, overdue or within a number of days like 7d")
+	watchCmd.Flags().String("search", "", "Only tasks whose content or description contains this text")
+
+	watchCmd.RegisterFlagCompletionFunc("project", completeProjects)
+	watchCmd.RegisterFlagCompletionFunc("section", completeSections)
+	watchCmd.RegisterFlagCompletionFunc("label", completeLabels)
+	watchCmd.RegisterFlagCompletionFunc("priority", completeList(priorityValues...))
+	watchCmd.RegisterFlagCompletionFunc("due", completeList("today", "tomorrow", "overdue", "7d"))
+}
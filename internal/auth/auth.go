@@ -8,20 +8,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/mdjarv/todoist-cli/internal/config"
 	"github.com/mdjarv/todoist-cli/internal/todoist"
 )
 
 const (
 	AuthURL      = "https://todoist.com/oauth/authorize"
-	Scopes       = "data:read_write,data:delete"
 	CallbackPort = 47829
 )
 
@@ -33,15 +33,32 @@ var (
 type Credentials struct {
 	AccessToken string `json:"access_token"`
 	TokenType   string `json:"token_type"`
+	// Scopes granted to an OAuth token, nil for personal tokens which have full access
+	Scopes []string `json:"scopes,omitempty"`
 }
 
-func Login() error {
-	if err := loadEnv(); err != nil {
-		return fmt.Errorf("failed to load .env file: %w", err)
+// LoginOptions configures the OAuth login flow
+type LoginOptions struct {
+	// Port is the local port receiving the OAuth redirect, CallbackPort if zero
+	Port int
+	// NoBrowser skips the local callback server and asks the user to paste the redirect URL or code
+	NoBrowser bool
+	// In is read for the pasted redirect URL or code in NoBrowser mode
+	In io.Reader
+	// Scope is the comma separated list of scopes to request, the scopes setting if empty
+	Scope string
+	// Encrypt stores the token encrypted with a passphrase, see saveCredentials
+	Encrypt bool
+}
+
+func Login(opts LoginOptions) error {
+	if err := loadClientCredentials(); err != nil {
+		return err
 	}
 
-	if ClientID == "" || ClientSecret == "" {
-		return fmt.Errorf("TODOIST_CLIENT_ID and TODOIST_CLIENT_SECRET must be set in .env file")
+	scopes, err := requestedScopes(opts.Scope)
+	if err != nil {
+		return err
 	}
 
 	state, err := generateRandomState()
@@ -49,14 +66,52 @@ func Login() error {
 		return fmt.Errorf("failed to generate state: %w", err)
 	}
 
-	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", CallbackPort))
+	port := opts.Port
+	if port == 0 {
+		port = CallbackPort
+	}
+
+	redirectURI := fmt.Sprintf("http://localhost:%d/callback", port)
+	authURL := buildAuthURL(redirectURI, state, scopes)
+
+	var code string
+	if opts.NoBrowser {
+		code, err = readPastedCode(opts.In, authURL, state)
+	} else {
+		code, err = waitForCallback(port, authURL, state)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to start local server on port %d: %w", CallbackPort, err)
+		return err
 	}
-	defer listener.Close()
 
-	redirectURI := fmt.Sprintf("http://localhost:%d/callback", CallbackPort)
-	authURL := buildAuthURL(redirectURI, state)
+	// Use the client for token exchange
+	client := todoist.NewClient("") // Empty token for OAuth calls
+	tokenResp, err := client.ExchangeCodeForToken(code, redirectURI, ClientID, ClientSecret)
+	if err != nil {
+		return fmt.Errorf("failed to exchange code for token: %w", err)
+	}
+
+	creds := &Credentials{
+		AccessToken: tokenResp.AccessToken,
+		TokenType:   tokenResp.TokenType,
+		Scopes:      scopes,
+	}
+
+	if err := saveCredentials(creds, opts.Encrypt); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	fmt.Printf("Successfully authenticated with scopes %s!\n", strings.Join(scopes, ","))
+	return nil
+}
+
+// waitForCallback serves the OAuth redirect on a local port and returns the authorization code
+func waitForCallback(port int, authURL, state string) (string, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", port))
+	if err != nil {
+		return "", fmt.Errorf("failed to start local server on port %d: %w", port, err)
+	}
+	defer listener.Close()
 
 	fmt.Printf("Please visit the following URL to authorize the application:\n\n%s\n\n", authURL)
 	fmt.Println("Waiting for authorization...")
@@ -84,31 +139,43 @@ func Login() error {
 	case code = <-codeChan:
 		// Success
 	case err := <-errChan:
-		return err
+		return "", err
 	case <-ctx.Done():
-		return fmt.Errorf("authorization timeout")
+		return "", fmt.Errorf("authorization timeout")
 	}
 
 	server.Shutdown(context.Background())
+	return code, nil
+}
 
-	// Use the client for token exchange
-	client := todoist.NewClient("") // Empty token for OAuth calls
-	tokenResp, err := client.ExchangeCodeForToken(code, redirectURI, ClientID, ClientSecret)
-	if err != nil {
-		return fmt.Errorf("failed to exchange code for token: %w", err)
-	}
+// readPastedCode asks the user to authorize on any machine and paste back the redirect URL or the bare code
+func readPastedCode(in io.Reader, authURL, state string) (string, error) {
+	fmt.Printf("Open the following URL in a browser on any machine to authorize the application:\n\n%s\n\n", authURL)
+	fmt.Println("After approving, the browser is redirected to a localhost address that will fail to load.")
+	fmt.Print("Paste the full URL from the address bar, or just the code parameter: ")
 
-	creds := &Credentials{
-		AccessToken: tokenResp.AccessToken,
-		TokenType:   tokenResp.TokenType,
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", fmt.Errorf("missing authorization code")
 	}
 
-	if err := saveCredentials(creds); err != nil {
-		return fmt.Errorf("failed to save credentials: %w", err)
+	if !strings.Contains(line, "?") && !strings.Contains(line, "=") {
+		return line, nil
 	}
 
-	fmt.Println("Successfully authenticated!")
-	return nil
+	query := line
+	if i := strings.Index(line, "?"); i >= 0 {
+		query = line[i+1:]
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse redirect URL: %w", err)
+	}
+	return parseCallback(values, state)
 }
 
 func generateRandomState() (string, error) {
@@ -119,10 +186,22 @@ func generateRandomState() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-func buildAuthURL(redirectURI, state string) string {
+// requestedScopes parses scope, falling back to the scopes setting
+func requestedScopes(scope string) ([]string, error) {
+	if scope == "" {
+		settings, err := config.Current()
+		if err != nil {
+			return nil, err
+		}
+		scope = settings.Scopes
+	}
+	return todoist.ParseScopes(scope)
+}
+
+func buildAuthURL(redirectURI, state string, scopes []string) string {
 	params := url.Values{
 		"client_id":     {ClientID},
-		"scope":         {Scopes},
+		"scope":         {strings.Join(scopes, ",")},
 		"state":         {state},
 		"redirect_uri":  {redirectURI},
 		"response_type": {"code"},
@@ -131,58 +210,159 @@ func buildAuthURL(redirectURI, state string) string {
 }
 
 func handleCallback(w http.ResponseWriter, r *http.Request, expectedState string, codeChan chan<- string, errChan chan<- error) {
-	if errParam := r.URL.Query().Get("error"); errParam != "" {
-		errChan <- fmt.Errorf("authorization error: %s", errParam)
-		http.Error(w, "Authorization failed", http.StatusBadRequest)
+	code, err := parseCallback(r.URL.Query(), expectedState)
+	if err != nil {
+		errChan <- err
+		http.Error(w, "Authorization failed: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	state := r.URL.Query().Get("state")
-	if state != expectedState {
-		errChan <- fmt.Errorf("invalid state parameter")
-		http.Error(w, "Invalid state parameter", http.StatusBadRequest)
-		return
+	fmt.Fprintf(w, "Authorization successful! You can close this window.")
+	codeChan <- code
+}
+
+// parseCallback validates the redirect query parameters and returns the authorization code
+func parseCallback(query url.Values, expectedState string) (string, error) {
+	if errParam := query.Get("error"); errParam != "" {
+		return "", fmt.Errorf("authorization error: %s", errParam)
 	}
 
-	code := r.URL.Query().Get("code")
-	if code == "" {
-		errChan <- fmt.Errorf("missing authorization code")
-		http.Error(w, "Missing authorization code", http.StatusBadRequest)
-		return
+	if query.Get("state") != expectedState {
+		return "", fmt.Errorf("invalid state parameter")
 	}
 
-	fmt.Fprintf(w, "Authorization successful! You can close this window.")
-	codeChan <- code
+	code := query.Get("code")
+	if code == "" {
+		return "", fmt.Errorf("missing authorization code")
+	}
+	return code, nil
 }
 
-func saveCredentials(creds *Credentials) error {
-	configDir := filepath.Join(os.Getenv("HOME"), ".config", "todoist")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return err
+// saveCredentials writes creds encrypted when encrypt is set, the encrypt_credentials setting is on,
+// TODOIST_PASSPHRASE is set or the existing credentials were encrypted, so the token never reaches disk in
+// plaintext then. A new passphrase is asked for twice.
+func saveCredentials(creds *Credentials, encrypt bool) error {
+	existing, err := readCredentialsFile()
+	wasEncrypted := err == nil && existing.Encrypted != nil
+	if !encrypt {
+		settings, err := config.Current()
+		if err != nil {
+			return err
+		}
+		encrypt = wasEncrypted || settings.IsEncryptCredentials() || os.Getenv(PassphraseEnv) != ""
+	}
+
+	if encrypt {
+		passphrase, err := readPassphrase(!wasEncrypted)
+		if err != nil {
+			return err
+		}
+		return writeEncryptedCredentials(creds, passphrase)
 	}
 
-	credentialsPath := filepath.Join(configDir, "credentials.json")
 	data, err := json.MarshalIndent(creds, "", "  ")
 	if err != nil {
 		return err
 	}
+	return writeCredentialsFile(data)
+}
+
+func writeEncryptedCredentials(creds *Credentials, passphrase string) error {
+	enc, err := encryptCredentials(creds, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt credentials: %w", err)
+	}
+
+	data, err := json.MarshalIndent(map[string]any{"encrypted": enc}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeCredentialsFile(data)
+}
 
+func writeCredentialsFile(data []byte) error {
+	if err := os.MkdirAll(config.ProfileDir(), 0755); err != nil {
+		return err
+	}
+
+	credentialsPath := config.ProfilePath("credentials.json")
 	return os.WriteFile(credentialsPath, data, 0600)
 }
 
+// LoadCredentials returns the token from TODOIST_API_TOKEN if set, otherwise the saved credentials
 func LoadCredentials() (*Credentials, error) {
-	credentialsPath := filepath.Join(os.Getenv("HOME"), ".config", "todoist", "credentials.json")
+	if token := os.Getenv(TokenEnv); token != "" {
+		return &Credentials{AccessToken: token, TokenType: "Bearer"}, nil
+	}
+	return loadStoredCredentials(true)
+}
+
+// LoadCredentialsNoPrompt is LoadCredentials for callers that must never block on the terminal.
+// Encrypted credentials are only decrypted with TODOIST_PASSPHRASE, otherwise ErrPassphraseRequired is returned.
+func LoadCredentialsNoPrompt() (*Credentials, error) {
+	if token := os.Getenv(TokenEnv); token != "" {
+		return &Credentials{AccessToken: token, TokenType: "Bearer"}, nil
+	}
+	return loadStoredCredentials(false)
+}
+
+// loadStoredCredentials reads the active profile's credentials file, prompting for the passphrase
+// of encrypted credentials only when prompt is set
+func loadStoredCredentials(prompt bool) (*Credentials, error) {
+	file, err := readCredentialsFile()
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Encrypted == nil {
+		return &file.Credentials, nil
+	}
+	if !prompt && os.Getenv(PassphraseEnv) == "" {
+		return nil, ErrPassphraseRequired
+	}
+
+	passphrase, err := readPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	return decryptCredentials(file.Encrypted, passphrase)
+}
+
+func readCredentialsFile() (*credentialsFile, error) {
+	credentialsPath := config.ProfilePath("credentials.json")
 	data, err := os.ReadFile(credentialsPath)
 	if err != nil {
 		return nil, err
 	}
 
-	var creds Credentials
-	if err := json.Unmarshal(data, &creds); err != nil {
+	var file credentialsFile
+	if err := json.Unmarshal(data, &file); err != nil {
 		return nil, err
 	}
 
-	return &creds, nil
+	return &file, nil
+}
+
+// loadClientCredentials loads the OAuth app's client ID and secret from the
+// environment or config file, falling back to a .env file in the working directory
+func loadClientCredentials() error {
+	settings, err := config.Current()
+	if err != nil {
+		return err
+	}
+	ClientID = settings.ClientID
+	ClientSecret = settings.ClientSecret
+
+	if ClientID == "" || ClientSecret == "" {
+		if err := loadEnv(); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to load .env file: %w", err)
+		}
+	}
+
+	if ClientID == "" || ClientSecret == "" {
+		return fmt.Errorf("client_id and client_secret must be set, with 'todoist config set' or TODOIST_CLIENT_ID and TODOIST_CLIENT_SECRET")
+	}
+	return nil
 }
 
 func loadEnv() error {
@@ -209,9 +389,13 @@ func loadEnv() error {
 
 		switch key {
 		case "TODOIST_CLIENT_ID":
-			ClientID = value
+			if ClientID == "" {
+				ClientID = value
+			}
 		case "TODOIST_CLIENT_SECRET":
-			ClientSecret = value
+			if ClientSecret == "" {
+				ClientSecret = value
+			}
 		}
 	}
 
@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+// PassphraseEnv names the environment variable holding the passphrase for encrypted credentials
+const PassphraseEnv = "TODOIST_PASSPHRASE"
+
+// ErrPassphraseRequired is returned by LoadCredentialsNoPrompt for encrypted credentials without TODOIST_PASSPHRASE
+var ErrPassphraseRequired = errors.New("credentials are encrypted, set " + PassphraseEnv + " to unlock them")
+
+// scrypt parameters recommended for interactive logins
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	keyLen  = 32
+)
+
+// Limits on the scrypt parameters read from a credentials file, so a tampered file cannot make
+// key derivation take forever or exhaust memory
+const (
+	minScryptN = 1 << 10
+	maxScryptN = 1 << 20
+	maxScryptR = 32
+	maxScryptP = 16
+)
+
+// encryptedCredentials is the on-disk form of credentials sealed with AES-GCM under a scrypt-derived key
+type encryptedCredentials struct {
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// credentialsFile is the credentials.json layout, either plaintext fields or an encrypted envelope
+type credentialsFile struct {
+	Credentials
+	Encrypted *encryptedCredentials `json:"encrypted,omitempty"`
+}
+
+// EncryptStoredCredentials rewrites the active profile's plaintext credentials encrypted with a new passphrase
+func EncryptStoredCredentials() error {
+	file, err := readCredentialsFile()
+	if err != nil {
+		return fmt.Errorf("failed to load credentials: %w", err)
+	}
+	if file.Encrypted != nil {
+		return fmt.Errorf("credentials are already encrypted")
+	}
+
+	passphrase, err := readPassphrase(true)
+	if err != nil {
+		return err
+	}
+	return writeEncryptedCredentials(&file.Credentials, passphrase)
+}
+
+func encryptCredentials(creds *Credentials, passphrase string) (*encryptedCredentials, error) {
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := &encryptedCredentials{KDF: "scrypt", N: scryptN, R: scryptR, P: scryptP, Salt: make([]byte, 16)}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := enc.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	enc.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(enc.Nonce); err != nil {
+		return nil, err
+	}
+	enc.Ciphertext = gcm.Seal(nil, enc.Nonce, plaintext, nil)
+	return enc, nil
+}
+
+func decryptCredentials(enc *encryptedCredentials, passphrase string) (*Credentials, error) {
+	if enc.KDF != "scrypt" {
+		return nil, fmt.Errorf("unsupported key derivation %q", enc.KDF)
+	}
+
+	gcm, err := enc.cipher(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := gcm.Open(nil, enc.Nonce, enc.Ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("wrong passphrase or corrupted credentials")
+	}
+
+	var creds Credentials
+	if err := json.Unmarshal(plaintext, &creds); err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
+
+// cipher derives the key from passphrase with the stored scrypt parameters
+func (enc *encryptedCredentials) cipher(passphrase string) (cipher.AEAD, error) {
+	if enc.N < minScryptN || enc.N > maxScryptN || enc.N&(enc.N-1) != 0 {
+		return nil, fmt.Errorf("invalid scrypt parameter N=%d, must be a power of two from %d to %d", enc.N, minScryptN, maxScryptN)
+	}
+	if enc.R < 1 || enc.R > maxScryptR || enc.P < 1 || enc.P > maxScryptP {
+		return nil, fmt.Errorf("invalid scrypt parameters r=%d p=%d, must be 1 to %d and 1 to %d", enc.R, enc.P, maxScryptR, maxScryptP)
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), enc.Salt, enc.N, enc.R, enc.P, keyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readPassphrase returns TODOIST_PASSPHRASE or prompts for the passphrase, twice when confirm is set
+func readPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("credentials are encrypted, set %s or run in a terminal for the passphrase", PassphraseEnv)
+	}
+
+	fmt.Fprint(os.Stderr, "Credentials passphrase: ")
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if len(passphrase) == 0 {
+		return "", errors.New("empty passphrase")
+	}
+
+	if confirm {
+		fmt.Fprint(os.Stderr, "Repeat passphrase: ")
+		again, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		if string(again) != string(passphrase) {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return string(passphrase), nil
+}
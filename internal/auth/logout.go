@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// Status describes the credentials of the active profile
+type Status struct {
+	Profile string
+	// Source is where the token came from: the TODOIST_API_TOKEN variable, the credentials file, or "" if none
+	Source string
+	// Scopes granted to the token, nil when unknown or unrestricted
+	Scopes []string
+	// User is set when the token was accepted by Todoist
+	User *todoist.User
+	// Err is set when the token could not be verified
+	Err error
+}
+
+// Logout revokes the stored OAuth token and deletes it, with localOnly skipping revocation
+func Logout(ctx context.Context, localOnly bool) error {
+	creds, err := loadStoredCredentials(true)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("not logged in to profile %q", config.Profile())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load credentials: %w", err)
+	}
+
+	if !localOnly {
+		if err := loadClientCredentials(); err != nil {
+			return fmt.Errorf("%w (use --local-only to only delete the local token)", err)
+		}
+		client := todoist.NewClient("")
+		if err := client.RevokeToken(ctx, ClientID, ClientSecret, creds.AccessToken); err != nil {
+			return fmt.Errorf("failed to revoke token: %w (use --local-only to only delete the local token)", err)
+		}
+	}
+
+	return DeleteCredentials()
+}
+
+// DeleteCredentials removes the active profile's credentials file
+func DeleteCredentials() error {
+	err := os.Remove(config.ProfilePath("credentials.json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// CheckStatus reports whether a token is present for the active profile and whether Todoist accepts it
+func CheckStatus(ctx context.Context) Status {
+	status := Status{Profile: config.Profile()}
+
+	creds, err := LoadCredentials()
+	if errors.Is(err, os.ErrNotExist) {
+		return status
+	}
+
+	status.Source = "credentials file"
+	if os.Getenv(TokenEnv) != "" {
+		status.Source = TokenEnv
+	} else if file, err := readCredentialsFile(); err == nil && file.Encrypted != nil {
+		status.Source = "encrypted credentials file"
+	}
+
+	if err != nil {
+		status.Err = err
+		return status
+	}
+
+	status.Scopes = creds.Scopes
+	status.User, status.Err = todoist.NewClient(creds.AccessToken).GetUser(ctx)
+	return status
+}
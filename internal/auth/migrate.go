@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// MigrateToken exchanges a personal API token for an OAuth token of the configured app and saves it,
+// requesting the scopes setting when scope is empty and encrypting it when encrypt is set
+func MigrateToken(ctx context.Context, personalToken, scope string, encrypt bool) error {
+	if err := loadClientCredentials(); err != nil {
+		return err
+	}
+	scopes, err := requestedScopes(scope)
+	if err != nil {
+		return err
+	}
+
+	client := todoist.NewClient("")
+	tokenResp, err := client.MigratePersonalToken(ctx, personalToken, ClientID, ClientSecret, strings.Join(scopes, ","))
+	if err != nil {
+		return fmt.Errorf("failed to migrate token: %w", err)
+	}
+
+	creds := &Credentials{
+		AccessToken: tokenResp.AccessToken,
+		TokenType:   tokenResp.TokenType,
+		Scopes:      scopes,
+	}
+
+	if err := saveCredentials(creds, encrypt); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	fmt.Println("Successfully migrated personal token to an OAuth token!")
+	return nil
+}
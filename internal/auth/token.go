@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"golang.org/x/term"
+)
+
+// TokenEnv names the environment variable holding a personal API token, which overrides stored credentials
+const TokenEnv = "TODOIST_API_TOKEN"
+
+// ReadToken reads a personal API token, prompting without echo when stdin is a terminal
+func ReadToken(in *os.File) (string, error) {
+	var token string
+	if term.IsTerminal(int(in.Fd())) {
+		fmt.Fprint(os.Stderr, "Todoist API token: ")
+		data, err := term.ReadPassword(int(in.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		token = string(data)
+	} else {
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		token = line
+	}
+
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("no token provided")
+	}
+	return token, nil
+}
+
+// LoginWithToken validates a personal API token against the user endpoint and saves it, encrypted when encrypt is set
+func LoginWithToken(ctx context.Context, token string, encrypt bool) error {
+	user, err := todoist.NewClient(token).GetUser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to validate token: %w", err)
+	}
+
+	creds := &Credentials{
+		AccessToken: token,
+		TokenType:   "Bearer",
+	}
+
+	if err := saveCredentials(creds, encrypt); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	fmt.Printf("Successfully authenticated as %s <%s>\n", user.FullName, user.Email)
+	return nil
+}
@@ -0,0 +1,292 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+const fileName = "cache.json"
+
+// resourceTypes are the Sync resources kept in the cache
+var resourceTypes = []string{"items", "projects", "sections", "labels"}
+
+// CompletedWindow is how far back completed tasks are looked up, Todoist allows at most three months per request
+const CompletedWindow = 28 * 24 * time.Hour
+
+// ErrEmpty is returned when offline data is requested but nothing has been cached yet
+var ErrEmpty = errors.New("no cached data available, run once while online first")
+
+// ErrNotSaved is returned when a sync succeeded but the cache could not be written, the synced data is still in memory
+var ErrNotSaved = errors.New("failed to save cache")
+
+// Cache is a local copy of the user's tasks and projects, kept up to date with the Sync API
+type Cache struct {
+	SyncToken string            `json:"sync_token"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Resources []string          `json:"resources"`
+	Tasks     []todoist.Task    `json:"tasks"`
+	Projects  []todoist.Project `json:"projects"`
+	Sections  []todoist.Section `json:"sections"`
+	Labels    []todoist.Label   `json:"labels"`
+
+	mu sync.Mutex
+}
+
+// Options controls how Fetch uses the cache
+type Options struct {
+	// MaxAge is how old the cache may be before it is refreshed
+	MaxAge time.Duration
+	// Offline disables network access and serves only cached data
+	Offline bool
+}
+
+// Load reads the cache from disk, returning an empty cache if none exists or it cannot be parsed
+func Load() (*Cache, error) {
+	data, err := os.ReadFile(config.ProfilePath(fileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Cache{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cache
+	if err := json.Unmarshal(data, &c); err != nil {
+		// A damaged cache is only a copy, the next sync replaces it
+		return &Cache{}, nil
+	}
+	return &c, nil
+}
+
+// Fetch loads the cache and refreshes it when it is older than opts.MaxAge
+func Fetch(ctx context.Context, client todoist.Client, opts Options) (*Cache, error) {
+	c, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Offline {
+		if c.Empty() {
+			return nil, ErrEmpty
+		}
+		return c, nil
+	}
+
+	if !c.Empty() && c.Age() <= opts.MaxAge {
+		return c, nil
+	}
+
+	if err := c.Refresh(ctx, client); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Empty reports whether the cache has never been synced
+func (c *Cache) Empty() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.SyncToken == ""
+}
+
+// Age returns the time since the cache was last synced
+func (c *Cache) Age() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Since(c.UpdatedAt)
+}
+
+// Snapshot returns copies of the cached tasks and a project ID to name map
+func (c *Cache) Snapshot() ([]todoist.Task, map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tasks := make([]todoist.Task, len(c.Tasks))
+	copy(tasks, c.Tasks)
+
+	projectNames := make(map[string]string)
+	for _, p := range c.Projects {
+		projectNames[p.ID] = p.Name
+	}
+	return tasks, projectNames
+}
+
+// ProjectSnapshot returns a copy of the cached projects
+func (c *Cache) ProjectSnapshot() []todoist.Project {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.Projects)
+}
+
+// Refresh pulls changes since the last sync token, or everything on first use, and saves the result
+func (c *Cache) Refresh(ctx context.Context, client todoist.Client) error {
+	_, err := c.Changes(ctx, client)
+	return err
+}
+
+// Changes refreshes the cache like Refresh and returns the tasks Todoist reported as changed,
+// including the deleted and completed ones the cache drops. After a full sync that is every open task.
+// The changes are returned along with ErrNotSaved when only saving failed.
+func (c *Cache) Changes(ctx context.Context, client todoist.Client) ([]todoist.Task, error) {
+	c.mu.Lock()
+	syncToken := c.SyncToken
+	// A cache written before a resource type was added needs a full sync to pick it up
+	if !slices.Equal(c.Resources, resourceTypes) {
+		syncToken = ""
+	}
+	c.mu.Unlock()
+
+	if syncToken == "" {
+		syncToken = "*"
+	}
+
+	resp, err := client.Sync(ctx, todoist.SyncRequest{
+		SyncToken:     syncToken,
+		ResourceTypes: resourceTypes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync: %w", err)
+	}
+	changed := slices.Clone(resp.Items)
+
+	c.mu.Lock()
+	if resp.FullSync {
+		c.Tasks = nil
+		c.Projects = nil
+		c.Sections = nil
+		c.Labels = nil
+	}
+	// Completed tasks and archived projects and sections are dropped along with deleted ones
+	c.Tasks = merge(c.Tasks, resp.Items,
+		func(t todoist.Task) string { return t.ID },
+		func(t todoist.Task) bool { return t.IsDeleted || t.Checked })
+	c.Projects = merge(c.Projects, resp.Projects,
+		func(p todoist.Project) string { return p.ID },
+		func(p todoist.Project) bool { return p.IsDeleted || p.IsArchived })
+	c.Sections = merge(c.Sections, resp.Sections,
+		func(s todoist.Section) string { return s.ID },
+		func(s todoist.Section) bool { return s.IsDeleted || s.IsArchived })
+	c.Labels = merge(c.Labels, resp.Labels,
+		func(l todoist.Label) string { return l.ID },
+		func(l todoist.Label) bool { return l.IsDeleted })
+	c.Resources = resourceTypes
+	c.SyncToken = resp.SyncToken
+	c.UpdatedAt = time.Now()
+	c.mu.Unlock()
+
+	if err := c.Save(); err != nil {
+		return changed, fmt.Errorf("%w: %w", ErrNotSaved, err)
+	}
+	return changed, nil
+}
+
+// CompletedSubtasks returns the subtasks of cached tasks that were completed within CompletedWindow.
+// The cache only holds open tasks, so they are fetched from Todoist.
+func (c *Cache) CompletedSubtasks(ctx context.Context, client todoist.Client) ([]todoist.Task, error) {
+	c.mu.Lock()
+	parents := make(map[string]bool, len(c.Tasks))
+	for _, t := range c.Tasks {
+		parents[t.ID] = true
+	}
+	c.mu.Unlock()
+
+	now := time.Now()
+	completed, err := todoist.ListAllCompletedTasks(ctx, client, todoist.CompletedTasksOptions{Since: now.Add(-CompletedWindow), Until: now, Limit: 200})
+	if err != nil {
+		return nil, err
+	}
+	var subtasks []todoist.Task
+	for _, t := range completed {
+		if parents[t.ParentID] {
+			t.Checked = true
+			subtasks = append(subtasks, t)
+		}
+	}
+	return subtasks, nil
+}
+
+// Invalidate marks the cache as stale so the next Fetch refreshes it, offline commands can still read it
+func (c *Cache) Invalidate() error {
+	c.mu.Lock()
+	c.UpdatedAt = time.Time{}
+	c.mu.Unlock()
+	return c.Save()
+}
+
+// Clear deletes the cache file of the active profile
+func Clear() error {
+	err := os.Remove(config.ProfilePath(fileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Save writes the cache to disk
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	data, err := json.Marshal(c)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(config.ProfileDir(), 0755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, so readers never see a partly written cache
+	tmp, err := os.CreateTemp(config.ProfileDir(), fileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), config.ProfilePath(fileName))
+}
+
+// merge applies updated items on top of the cached ones by ID, dropping those removed reports as gone
+func merge[T any](cached, updated []T, id func(T) string, removed func(T) bool) []T {
+	index := make(map[string]int, len(cached))
+	for i, item := range cached {
+		index[id(item)] = i
+	}
+
+	gone := make(map[string]bool)
+	for _, item := range updated {
+		if removed(item) {
+			gone[id(item)] = true
+			continue
+		}
+		if i, ok := index[id(item)]; ok {
+			cached[i] = item
+		} else {
+			index[id(item)] = len(cached)
+			cached = append(cached, item)
+		}
+	}
+
+	merged := cached[:0]
+	for _, item := range cached {
+		if !gone[id(item)] {
+			merged = append(merged, item)
+		}
+	}
+	return merged
+}
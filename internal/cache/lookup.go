@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// ErrNotFound is wrapped by lookups that match nothing, so callers can refresh and retry
+var ErrNotFound = errors.New("not found")
+
+// Task returns the cached task with the given ID
+func (c *Cache) Task(id string) (todoist.Task, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.Tasks {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return todoist.Task{}, false
+}
+
+// FindProject returns the project with the given ID, or else the one whose name matches case-insensitively
+func (c *Cache) FindProject(nameOrID string) (todoist.Project, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var matches []todoist.Project
+	for _, p := range c.Projects {
+		if p.ID == nameOrID {
+			return p, nil
+		}
+		if strings.EqualFold(p.Name, nameOrID) {
+			matches = append(matches, p)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return todoist.Project{}, fmt.Errorf("project %q: %w", nameOrID, ErrNotFound)
+	case 1:
+		return matches[0], nil
+	default:
+		return todoist.Project{}, fmt.Errorf("project name %q is ambiguous, use one of the IDs: %s", nameOrID, projectIDs(matches))
+	}
+}
+
+// FindSection returns the section with the given ID, or else the one whose name matches case-insensitively.
+// Names are looked up within projectID, or across all projects when it is empty.
+func (c *Cache) FindSection(projectID, nameOrID string) (todoist.Section, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var matches []todoist.Section
+	for _, s := range c.Sections {
+		if s.ID == nameOrID {
+			return s, nil
+		}
+		if strings.EqualFold(s.Name, nameOrID) && (projectID == "" || s.ProjectID == projectID) {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return todoist.Section{}, fmt.Errorf("section %q: %w", nameOrID, ErrNotFound)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, s := range matches {
+			ids[i] = s.ID
+		}
+		return todoist.Section{}, fmt.Errorf("section name %q is ambiguous, pass --project or use one of the IDs: %s", nameOrID, strings.Join(ids, ", "))
+	}
+}
+
+func projectIDs(projects []todoist.Project) string {
+	ids := make([]string, len(projects))
+	for i, p := range projects {
+		ids[i] = p.ID
+	}
+	return strings.Join(ids, ", ")
+}
+
+// Names maps project and section IDs to their names
+type Names struct {
+	Projects map[string]string
+	Sections map[string]string
+}
+
+// Names returns the names of all cached projects and sections
+func (c *Cache) Names() Names {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := Names{Projects: make(map[string]string), Sections: make(map[string]string)}
+	for _, p := range c.Projects {
+		names.Projects[p.ID] = p.Name
+	}
+	for _, s := range c.Sections {
+		names.Sections[s.ID] = s.Name
+	}
+	return names
+}
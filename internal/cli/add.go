@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/queue"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// AddOptions describes a task to create
+type AddOptions struct {
+	Task todoist.CreateTaskOptions
+	// Project and Section are names or IDs, resolved against the cache
+	Project string
+	Section string
+	// Cache controls how the cache used for resolving names is refreshed
+	Cache cache.Options
+}
+
+// Add creates a task and prints it
+func Add(ctx context.Context, client todoist.Client, opts AddOptions, out output.Options) error {
+	options := opts.Task
+	var store *cache.Cache
+	if opts.Project != "" || opts.Section != "" {
+		var err error
+		store, err = cache.Fetch(ctx, client, opts.Cache)
+		if err != nil {
+			return fmt.Errorf("failed to fetch projects: %w", err)
+		}
+		if err := resolveLocation(ctx, client, store, opts, &options); err != nil {
+			return err
+		}
+	} else if cached, err := cache.Load(); err == nil {
+		// Only used for showing project names, so a missing cache is fine
+		store = cached
+	}
+
+	task, err := client.CreateTask(ctx, options)
+	if errors.Is(err, queue.ErrQueued) {
+		fmt.Fprintln(os.Stderr, err)
+		return printTask(*task, cacheNames(store), out)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	// Prefer the synced copy, which has dates and other fields filled in by Todoist
+	if !opts.Cache.Offline && store != nil && store.Refresh(ctx, client) == nil {
+		if synced, ok := store.Task(task.ID); ok {
+			task = &synced
+		}
+	}
+	return printTask(*task, cacheNames(store), out)
+}
+
+// cacheNames returns the project and section names of store, none if there is no cache
+func cacheNames(store *cache.Cache) cache.Names {
+	if store == nil {
+		return cache.Names{}
+	}
+	return store.Names()
+}
+
+// resolveLocation sets the project and section IDs from their names
+func resolveLocation(ctx context.Context, client todoist.Client, store *cache.Cache, opts AddOptions, options *todoist.CreateTaskOptions) error {
+	return findWithRefresh(ctx, client, store, opts.Cache.Offline, func() error {
+		return lookupLocation(store, opts, options)
+	})
+}
+
+// findWithRefresh runs lookup against the cache, syncing once and retrying if a name was not cached yet
+func findWithRefresh(ctx context.Context, client todoist.Client, store *cache.Cache, offline bool, lookup func() error) error {
+	err := lookup()
+	if errors.Is(err, cache.ErrNotFound) && !offline {
+		if err := store.Refresh(ctx, client); err != nil {
+			return err
+		}
+		err = lookup()
+	}
+	return err
+}
+
+func lookupLocation(store *cache.Cache, opts AddOptions, options *todoist.CreateTaskOptions) error {
+	if opts.Project != "" {
+		project, err := store.FindProject(opts.Project)
+		if err != nil {
+			return err
+		}
+		options.ProjectID = project.ID
+	}
+	if opts.Section != "" {
+		section, err := store.FindSection(options.ProjectID, opts.Section)
+		if err != nil {
+			return err
+		}
+		options.SectionID = section.ID
+		options.ProjectID = section.ProjectID
+	}
+	return nil
+}
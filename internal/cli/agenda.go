@@ -0,0 +1,233 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// AgendaOptions selects the days of an agenda view
+type AgendaOptions struct {
+	// Days is how many days starting today are shown, each under its own heading
+	Days int
+	// Overdue adds the tasks due before today
+	Overdue bool
+	// GroupOverdue puts all overdue tasks under one heading instead of one per day
+	GroupOverdue bool
+	// Capacity is how much planned time fits in a day, 0 to never flag a day as overbooked
+	Capacity time.Duration
+	Cache    cache.Options
+}
+
+// AgendaDay is the tasks due on one day, or all overdue tasks when Date is empty
+type AgendaDay struct {
+	Date  string         `json:"date,omitempty"`
+	Title string         `json:"title"`
+	Tasks []todoist.Task `json:"tasks"`
+	// Planned is the total duration of the tasks in minutes
+	Planned    int  `json:"planned_minutes"`
+	Overbooked bool `json:"overbooked"`
+}
+
+// agendaOrder sorts the tasks of a day as arranged in Todoist, then by priority and time
+var agendaOrder = []query.SortKey{{Field: "day"}, {Field: "priority"}, {Field: "due"}}
+
+// agendaColumns are shown for each day unless --columns is given
+var agendaColumns = []string{"time", "id", "priority", "content", "project", "duration", "labels"}
+
+const minutesPerDay = 24 * 60
+
+var (
+	agendaHeading    = lipgloss.NewStyle().Bold(true)
+	agendaOverdue    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(overdueColor))
+	agendaPlanned    = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	agendaOverbooked = lipgloss.NewStyle().Foreground(lipgloss.Color(overdueColor))
+)
+
+var agendaTable = output.Table[AgendaDay]{
+	Default: []string{"date", "title", "tasks", "planned", "overbooked"},
+	Columns: []output.Column[AgendaDay]{
+		{Name: "date", Header: "Date", Value: func(d AgendaDay) string { return d.Date }},
+		{Name: "title", Header: "Day", Value: func(d AgendaDay) string { return d.Title }},
+		{Name: "tasks", Header: "Tasks", Value: func(d AgendaDay) string { return strconv.Itoa(len(d.Tasks)) }},
+		{Name: "planned", Header: "Planned", Value: func(d AgendaDay) string { return formatPlanned(d.Planned) }},
+		{Name: "overbooked", Header: "Overbooked", Value: func(d AgendaDay) string { return strconv.FormatBool(d.Overbooked) }},
+	},
+}
+
+// Agenda prints open tasks grouped by the day they are due, with the planned time per day.
+// Tables show each day's tasks under a heading, other formats the AgendaDay list.
+func Agenda(ctx context.Context, client todoist.Client, opts AgendaOptions, out output.Options) error {
+	store, err := cache.Fetch(ctx, client, opts.Cache)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	tasks, _ := store.Snapshot()
+	days := buildAgenda(tasks, opts, out.DateFormat, time.Now())
+
+	if out.Structured() {
+		return agendaTable.Write(out, days)
+	}
+	return renderAgenda(days, store.Names(), out)
+}
+
+// buildAgenda groups the open tasks by due day, in order and including days without tasks
+func buildAgenda(tasks []todoist.Task, opts AgendaOptions, dateFormat string, now time.Time) []AgendaDay {
+	if dateFormat == "" {
+		dateFormat = "2006-01-02"
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	byDate := make(map[string]*AgendaDay)
+	var overdue []*AgendaDay
+	var upcoming []*AgendaDay
+	for i := range opts.Days {
+		day := today.AddDate(0, 0, i)
+		d := &AgendaDay{Date: day.Format("2006-01-02"), Title: dayTitle(day, today, dateFormat)}
+		byDate[d.Date] = d
+		upcoming = append(upcoming, d)
+	}
+	overdueGroup := &AgendaDay{Title: "Overdue"}
+
+	for _, t := range tasks {
+		due, ok := query.DueTime(t)
+		if t.Checked || !ok {
+			continue
+		}
+		due = due.Local()
+		date := due.Format("2006-01-02")
+
+		d, ok := byDate[date]
+		switch {
+		case ok:
+		case !opts.Overdue || !(query.Filter{Due: "overdue"}).Match(t, now):
+			continue
+		case opts.GroupOverdue:
+			d = overdueGroup
+		default:
+			day := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.Local)
+			d = &AgendaDay{Date: date, Title: dayTitle(day, today, dateFormat)}
+			byDate[date] = d
+			overdue = append(overdue, d)
+		}
+		d.Tasks = append(d.Tasks, t)
+	}
+
+	var groups []*AgendaDay
+	if opts.GroupOverdue && len(overdueGroup.Tasks) > 0 {
+		groups = append(groups, overdueGroup)
+	}
+	slices.SortFunc(overdue, func(a, b *AgendaDay) int { return strings.Compare(a.Date, b.Date) })
+	groups = append(groups, overdue...)
+	groups = append(groups, upcoming...)
+
+	days := make([]AgendaDay, len(groups))
+	for i, d := range groups {
+		query.Sort(d.Tasks, agendaOrder, nil)
+		if d.Tasks == nil {
+			d.Tasks = []todoist.Task{}
+		}
+		for _, t := range d.Tasks {
+			d.Planned += plannedMinutes(t)
+		}
+		d.Overbooked = opts.Capacity > 0 && time.Duration(d.Planned)*time.Minute > opts.Capacity
+		days[i] = *d
+	}
+	return days
+}
+
+// dayTitle names a day by weekday and date, marking today and tomorrow
+func dayTitle(day, today time.Time, dateFormat string) string {
+	title := day.Format("Monday") + " " + day.Format(dateFormat)
+	switch {
+	case day.Equal(today):
+		title += " · Today"
+	case day.Equal(today.AddDate(0, 0, 1)):
+		title += " · Tomorrow"
+	}
+	return title
+}
+
+// plannedMinutes is the task's duration in minutes, a whole day for durations in days
+func plannedMinutes(t todoist.Task) int {
+	amount, _ := t.Duration["amount"].(float64)
+	if unit, _ := t.Duration["unit"].(string); unit == "day" {
+		return int(amount) * minutesPerDay
+	}
+	return int(amount)
+}
+
+func formatPlanned(minutes int) string {
+	if minutes == 0 {
+		return ""
+	}
+	return FormatDuration(minutes, "minute")
+}
+
+// renderAgenda writes a heading with the planned time for every day, followed by its tasks
+func renderAgenda(days []AgendaDay, names cache.Names, out output.Options) error {
+	style := func(s lipgloss.Style, text string) string {
+		if !out.Color {
+			return text
+		}
+		return s.Render(text)
+	}
+	out.NoHeader = true
+	table := taskTable(names, out.DateFormat)
+	columns := out.Columns
+
+	for i, d := range days {
+		if i > 0 {
+			fmt.Println()
+		}
+		heading := style(agendaHeading, d.Title)
+		if d.Date == "" {
+			heading = style(agendaOverdue, d.Title)
+		}
+		var summary []string
+		switch len(d.Tasks) {
+		case 0:
+		case 1:
+			summary = append(summary, "1 task")
+		default:
+			summary = append(summary, fmt.Sprintf("%d tasks", len(d.Tasks)))
+		}
+		if d.Planned > 0 {
+			summary = append(summary, formatPlanned(d.Planned)+" planned")
+		}
+		line := heading
+		if len(summary) > 0 {
+			line += "  " + style(agendaPlanned, strings.Join(summary, ", "))
+		}
+		if d.Overbooked {
+			line += "  " + style(agendaOverbooked, "overbooked")
+		}
+		fmt.Println(line)
+
+		if len(d.Tasks) == 0 {
+			fmt.Println(style(agendaPlanned, "  No tasks"))
+			continue
+		}
+		out.Columns = columns
+		if len(columns) == 0 {
+			out.Columns = agendaColumns
+			if d.Date == "" {
+				// The overdue group spans several days, so show the full due date
+				out.Columns = slices.Replace(slices.Clone(agendaColumns), 0, 1, "due")
+			}
+		}
+		if err := table.Write(out, d.Tasks); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/queue"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+func TestBuildAgenda(t *testing.T) {
+	// now is a Monday noon, in local time like the due dates Todoist sends without a timezone
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+	minutes := func(n float64) map[string]any { return map[string]any{"amount": n, "unit": "minute"} }
+	due := func(date string) map[string]any { return map[string]any{"date": date} }
+
+	tasks := []todoist.Task{
+		{ID: "later", Priority: 4, DayOrder: 2, Due: due("2026-10-19"), Duration: minutes(30)},
+		{ID: "first", Priority: 1, DayOrder: 1, Due: due("2026-10-19T09:00:00")},
+		{ID: "urgent", Priority: 4, DayOrder: -1, Due: due("2026-10-19")},
+		{ID: "done", Priority: 4, DayOrder: 0, Due: due("2026-10-19"), Checked: true},
+		{ID: "meeting", Due: due("2026-10-20T10:00:00"), Duration: minutes(90)},
+		{ID: "offsite", Due: due("2026-10-20"), Duration: map[string]any{"amount": float64(1), "unit": "day"}},
+		{ID: "late", Due: due("2026-10-17")},
+		{ID: "yesterday", Due: due("2026-10-18")},
+		{ID: "next-week", Due: due("2026-10-26")},
+		{ID: "undated"},
+	}
+	// A queued task has no day order yet, so it sorts after the ones Todoist arranged
+	journal := &queue.Journal{Pending: []queue.Mutation{
+		{Command: todoist.AddTaskCommand(todoist.CreateTaskOptions{Content: "queued", Priority: 3, DueDate: "2026-10-19"})},
+	}}
+	tasks = journal.Apply(tasks)
+	queuedID := tasks[len(tasks)-1].ID
+
+	type day struct {
+		date, title string
+		tasks       []string
+		planned     int
+		overbooked  bool
+	}
+	tests := []struct {
+		name string
+		opts AgendaOptions
+		want []day
+	}{
+		{
+			name: "today",
+			opts: AgendaOptions{Days: 1},
+			want: []day{
+				{"2026-10-19", "Monday 2026-10-19 · Today", []string{"first", "later", "urgent", queuedID}, 30, false},
+			},
+		},
+		{
+			name: "upcoming with overdue days and capacity",
+			opts: AgendaOptions{Days: 3, Overdue: true, Capacity: 8 * time.Hour},
+			want: []day{
+				{"2026-10-17", "Saturday 2026-10-17", []string{"late"}, 0, false},
+				{"2026-10-18", "Sunday 2026-10-18", []string{"yesterday"}, 0, false},
+				{"2026-10-19", "Monday 2026-10-19 · Today", []string{"first", "later", "urgent", queuedID}, 30, false},
+				{"2026-10-20", "Tuesday 2026-10-20 · Tomorrow", []string{"offsite", "meeting"}, 90 + minutesPerDay, true},
+				{"2026-10-21", "Wednesday 2026-10-21", []string{}, 0, false},
+			},
+		},
+		{
+			name: "overdue grouped",
+			opts: AgendaOptions{Days: 1, Overdue: true, GroupOverdue: true, Capacity: 20 * time.Minute},
+			want: []day{
+				{"", "Overdue", []string{"late", "yesterday"}, 0, false},
+				{"2026-10-19", "Monday 2026-10-19 · Today", []string{"first", "later", "urgent", queuedID}, 30, true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []day
+			for _, d := range buildAgenda(tasks, tt.opts, "", now) {
+				ids := []string{}
+				for _, task := range d.Tasks {
+					ids = append(ids, task.ID)
+				}
+				got = append(got, day{d.Date, d.Title, ids, d.Planned, d.Overbooked})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildAgenda() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// CalendarOptions configures the calendar feed server
+type CalendarOptions struct {
+	// Addr is the address to listen on, like 127.0.0.1:8765
+	Addr string
+	// Refresh is how often the cache is synced with Todoist, or reread from disk when offline
+	Refresh time.Duration
+	Offline bool
+}
+
+// calendarServer serves feeds from a cache that is refreshed in the background
+type calendarServer struct {
+	store atomic.Pointer[cache.Cache]
+}
+
+// ServeCalendar serves iCalendar feeds of the cached open tasks until ctx is done:
+// /tasks.ics, filtered by the project, section, label, priority, due and search parameters,
+// and /projects/{project}.ics for one project by name or ID.
+func ServeCalendar(ctx context.Context, client todoist.Client, opts CalendarOptions) error {
+	store, err := cache.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load cache: %w", err)
+	}
+	s := &calendarServer{}
+	s.store.Store(store)
+
+	listener, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", opts.Addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /tasks.ics", s.handleFeed)
+	mux.HandleFunc("GET /projects/{project}", s.handleFeed)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go s.refresh(ctx, client, opts)
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+
+	fmt.Printf("Serving calendar feeds on http://%s/\n", listener.Addr())
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server error: %w", err)
+	}
+	return nil
+}
+
+// refresh syncs the cache right away and then every opts.Refresh, or rereads it from disk when offline
+func (s *calendarServer) refresh(ctx context.Context, client todoist.Client, opts CalendarOptions) {
+	ticker := time.NewTicker(opts.Refresh)
+	defer ticker.Stop()
+	for {
+		var err error
+		if opts.Offline {
+			var store *cache.Cache
+			if store, err = cache.Load(); err == nil {
+				s.store.Store(store)
+			}
+		} else {
+			err = s.store.Load().Refresh(ctx, client)
+		}
+		if err != nil && ctx.Err() == nil {
+			fmt.Fprintln(os.Stderr, "failed to refresh cache:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// handleIndex lists the feeds that can be subscribed to
+func (s *calendarServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	store := s.store.Load()
+	base := "http://" + r.Host
+	fmt.Fprintln(w, "All open tasks:")
+	fmt.Fprintf(w, "  %s/tasks.ics\n\n", base)
+	fmt.Fprintln(w, "Projects:")
+	for _, p := range projectsInOrder(store.ProjectSnapshot()) {
+		fmt.Fprintf(w, "  %s/projects/%s.ics\n", base, url.PathEscape(p.Name))
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Filter /tasks.ics with the project, section, label, priority, due and search parameters,")
+	fmt.Fprintf(w, "e.g. %s/tasks.ics?label=errand&due=7d\n", base)
+}
+
+// handleFeed writes the feed for the request's project and filter parameters
+func (s *calendarServer) handleFeed(w http.ResponseWriter, r *http.Request) {
+	store := s.store.Load()
+	filter, name, err := feedFilter(store, r)
+	if errors.Is(err, cache.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	opts := ICSOptions{Name: name, Events: true}
+	if err := WriteICS(&buf, openTasks(store, filter), store.Names().Projects, opts, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// feedFilter builds the filter of a feed from the project in its path and its query parameters,
+// along with a name for the calendar
+func feedFilter(store *cache.Cache, r *http.Request) (query.Filter, string, error) {
+	params := r.URL.Query()
+	filter := query.Filter{Labels: params["label"], Due: params.Get("due"), Search: params.Get("search")}
+	name := "Todoist"
+
+	project := params.Get("project")
+	if p := r.PathValue("project"); p != "" {
+		var ok bool
+		if project, ok = strings.CutSuffix(p, ".ics"); !ok {
+			return filter, "", fmt.Errorf("feed %q: %w", p, cache.ErrNotFound)
+		}
+	}
+	if project != "" {
+		p, err := store.FindProject(project)
+		if err != nil {
+			return filter, "", err
+		}
+		filter.ProjectID = p.ID
+		name = projectPath(store, p.ID)
+	}
+	if section := params.Get("section"); section != "" {
+		s, err := store.FindSection(filter.ProjectID, section)
+		if err != nil {
+			return filter, "", err
+		}
+		filter.SectionID = s.ID
+		name = projectPath(store, s.ProjectID) + projectSeparator + s.Name
+	}
+
+	if priority := params.Get("priority"); priority != "" {
+		var err error
+		if filter.Priority, err = ParsePriority(priority); err != nil {
+			return filter, "", err
+		}
+	}
+	if filter.Due != "" {
+		if err := query.ValidateDue(filter.Due); err != nil {
+			return filter, "", err
+		}
+	}
+	return filter, name, nil
+}
@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/queue"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// SelectOptions controls how task arguments, IDs or queries, are turned into tasks
+type SelectOptions struct {
+	// Prompt reads answers for ambiguous or fuzzy matches, nil when not interactive
+	Prompt *bufio.Reader
+	// Yes acts on a single fuzzy match without asking
+	Yes bool
+	// Cache controls how the cache holding open tasks is refreshed
+	Cache cache.Options
+}
+
+// TaskResult is a task changed by a command, and whether the change is queued rather than sent
+type TaskResult struct {
+	Task   todoist.Task `json:"task"`
+	Queued bool         `json:"queued"`
+}
+
+// Done completes the tasks given by ID or content query
+func Done(ctx context.Context, client todoist.Client, args []string, opts SelectOptions, out output.Options) error {
+	store, err := cache.Fetch(ctx, client, opts.Cache)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	tasks, _ := store.Snapshot()
+
+	var open []todoist.Task
+	for _, t := range tasks {
+		if !t.Checked {
+			open = append(open, t)
+		}
+	}
+
+	selected, err := selectTasks(args, open, opts, "complete")
+	if err != nil {
+		return err
+	}
+	results, err := applyTasks(ctx, selected, client.CloseTask)
+	if anySent(results) {
+		refreshCache(ctx, client, store)
+	}
+	for i := range results {
+		results[i].Task.Checked = true
+	}
+	if printErr := printResults(results, "Completed", store.Names(), out); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+// Reopen uncompletes the tasks given by ID, or by a query matching a task completed in the last four weeks
+func Reopen(ctx context.Context, client todoist.Client, args []string, opts SelectOptions, out output.Options) error {
+	var completed []todoist.Task
+	if !opts.Cache.Offline {
+		var err error
+		completed, err = recentlyCompleted(ctx, client)
+		if err != nil {
+			return fmt.Errorf("failed to fetch completed tasks: %w", err)
+		}
+	}
+
+	selected, err := selectTasks(args, completed, opts, "reopen")
+	if err != nil {
+		return err
+	}
+	results, err := applyTasks(ctx, selected, client.ReopenTask)
+	// Only used for project names and refreshing, so a missing cache is fine
+	store, _ := cache.Load()
+	if store != nil && anySent(results) {
+		refreshCache(ctx, client, store)
+	}
+	for i := range results {
+		results[i].Task.Checked = false
+	}
+	if printErr := printResults(results, "Reopened", cacheNames(store), out); printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+func recentlyCompleted(ctx context.Context, client todoist.Client) ([]todoist.Task, error) {
+	now := time.Now()
+	return todoist.ListAllCompletedTasks(ctx, client, todoist.CompletedTasksOptions{Since: now.Add(-cache.CompletedWindow), Until: now, Limit: 200})
+}
+
+// selectTasks resolves every argument before anything is changed, so one bad argument aborts the whole command
+func selectTasks(args []string, candidates []todoist.Task, opts SelectOptions, verb string) ([]todoist.Task, error) {
+	var selected []todoist.Task
+	seen := make(map[string]bool)
+	add := func(t todoist.Task) {
+		if !seen[t.ID] {
+			seen[t.ID] = true
+			selected = append(selected, t)
+		}
+	}
+
+	for _, arg := range args {
+		if task, ok := findByID(candidates, arg); ok {
+			add(task)
+			continue
+		}
+
+		matches := FuzzyMatch(arg, candidates)
+		switch {
+		case len(matches) == 0 && looksLikeID(arg):
+			// Not cached, let Todoist decide whether the ID exists
+			add(todoist.Task{ID: arg})
+		case len(matches) == 0:
+			return nil, fmt.Errorf("no task matches %q", arg)
+		case len(matches) == 1 && opts.Yes:
+			add(matches[0])
+		case len(matches) == 1 && opts.Prompt == nil:
+			return nil, fmt.Errorf("%q matched %q, pass its ID or --yes to %s fuzzy matches", arg, matches[0].Content, verb)
+		case len(matches) == 1:
+			ok, err := confirm(opts.Prompt, fmt.Sprintf("%s %q?", capitalize(verb), matches[0].Content))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				add(matches[0])
+			}
+		case opts.Prompt == nil || opts.Yes:
+			return nil, fmt.Errorf("%q matches %d tasks, pass one of the IDs:\n%s", arg, len(matches), describeTasks(matches))
+		default:
+			chosen, err := choose(opts.Prompt, arg, matches, verb)
+			if err != nil {
+				return nil, err
+			}
+			for _, t := range chosen {
+				add(t)
+			}
+		}
+	}
+	return selected, nil
+}
+
+// applyTasks runs op on every task, carrying on past failures and reporting them at the end.
+// It returns the tasks op succeeded on.
+func applyTasks(ctx context.Context, tasks []todoist.Task, op func(context.Context, string) error) ([]TaskResult, error) {
+	var results []TaskResult
+	for _, task := range tasks {
+		err := op(ctx, task.ID)
+		switch {
+		case errors.Is(err, queue.ErrQueued):
+			results = append(results, TaskResult{Task: task, Queued: true})
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "failed on %q: %v\n", taskLabel(task), err)
+		default:
+			results = append(results, TaskResult{Task: task})
+		}
+	}
+	if failed := len(tasks) - len(results); failed > 0 {
+		return results, fmt.Errorf("%d of %d tasks failed", failed, len(tasks))
+	}
+	return results, nil
+}
+
+// anySent reports whether any of the changes reached Todoist rather than being queued
+func anySent(results []TaskResult) bool {
+	return slices.ContainsFunc(results, func(r TaskResult) bool { return !r.Queued })
+}
+
+// printResults writes the changed tasks, as a line each saying what happened unless the output is structured or has columns
+func printResults(results []TaskResult, past string, names cache.Names, out output.Options) error {
+	if out.Structured() || len(out.Columns) > 0 {
+		return resultTable(names, out.DateFormat).Write(out, results)
+	}
+	for _, r := range results {
+		printResult(r, past)
+	}
+	return nil
+}
+
+func printResult(r TaskResult, past string) {
+	if r.Queued {
+		fmt.Printf("%s %q (queued, will be sent on the next sync)\n", past, taskLabel(r.Task))
+	} else {
+		fmt.Printf("%s %q\n", past, taskLabel(r.Task))
+	}
+}
+
+// taskLabel names a task by its content, or by its ID when only that is known
+func taskLabel(t todoist.Task) string {
+	if t.Content == "" {
+		return t.ID
+	}
+	return t.Content
+}
+
+// refreshCache syncs store after changes reached Todoist so the next command sees them,
+// or marks it stale when that fails so the next online command syncs regardless of its age.
+// It reports whether store now holds the changes.
+func refreshCache(ctx context.Context, client todoist.Client, store *cache.Cache) bool {
+	if err := store.Refresh(ctx, client); err != nil {
+		if err := store.Invalidate(); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to update cache:", err)
+		}
+		return false
+	}
+	return true
+}
+
+func findByID(tasks []todoist.Task, id string) (todoist.Task, bool) {
+	for _, t := range tasks {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return todoist.Task{}, false
+}
+
+func describeTasks(tasks []todoist.Task) string {
+	var b strings.Builder
+	for _, t := range tasks {
+		fmt.Fprintf(&b, "  %s\t%s\n", t.ID, t.Content)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// confirm asks a yes/no question on stderr, defaulting to no
+func confirm(in *bufio.Reader, question string) (bool, error) {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes", nil
+}
+
+// choose lists the matches on stderr and reads the numbers of the ones to act on, "a" for all or empty for none
+func choose(in *bufio.Reader, query string, matches []todoist.Task, verb string) ([]todoist.Task, error) {
+	fmt.Fprintf(os.Stderr, "%q matches %d tasks:\n", query, len(matches))
+	for i, t := range matches {
+		fmt.Fprintf(os.Stderr, "  %d) %s\n", i+1, t.Content)
+	}
+	fmt.Fprintf(os.Stderr, "Which to %s? (numbers separated by spaces, a for all, empty for none) ", verb)
+
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		return nil, fmt.Errorf("failed to read answer: %w", err)
+	}
+	line = strings.TrimSpace(line)
+	if strings.EqualFold(line, "a") {
+		return matches, nil
+	}
+
+	var chosen []todoist.Task
+	for _, field := range strings.Fields(strings.ReplaceAll(line, ",", " ")) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(matches) {
+			return nil, fmt.Errorf("invalid choice %q", field)
+		}
+		chosen = append(chosen, matches[n-1])
+	}
+	return chosen, nil
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
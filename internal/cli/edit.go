@@ -0,0 +1,344 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/queue"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+// taskFrontMatter is the YAML header of a task opened with 'todoist edit'
+type taskFrontMatter struct {
+	Project  string   `yaml:"project"`
+	Section  string   `yaml:"section"`
+	Labels   []string `yaml:"labels,flow"`
+	Priority string   `yaml:"priority"`
+	Due      string   `yaml:"due"`
+	Deadline string   `yaml:"deadline"`
+	Duration string   `yaml:"duration"`
+}
+
+// taskDocument is a task as edited in $EDITOR: front matter, then the content line and the description
+type taskDocument struct {
+	taskFrontMatter
+	Content     string
+	Description string
+}
+
+// taskChanges are the requests needed to turn the original task into the edited one
+type taskChanges struct {
+	update  todoist.UpdateTaskOptions
+	changed bool
+	move    *todoist.MoveTaskOptions
+}
+
+// Edit opens a task in $EDITOR and sends the fields that changed, re-opening the editor on invalid input
+func Edit(ctx context.Context, client todoist.Client, id string, opts cache.Options, out output.Options) error {
+	store, err := cache.Fetch(ctx, client, opts)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	task, ok := store.Task(id)
+	if !ok && !opts.Offline {
+		if err := store.Refresh(ctx, client); err != nil {
+			return err
+		}
+		task, ok = store.Task(id)
+	}
+	if !ok {
+		return fmt.Errorf("task %q not found", id)
+	}
+
+	original := documentFromTask(task, store)
+
+	f, err := os.CreateTemp("", "todoist-task-*.md")
+	if err != nil {
+		return err
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	text := original.render()
+	var changes *taskChanges
+	for {
+		if err := os.WriteFile(path, text, 0600); err != nil {
+			return err
+		}
+		if err := OpenEditor(path); err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		text = stripComments(data)
+		if len(bytes.TrimSpace(text)) == 0 {
+			fmt.Fprintln(os.Stderr, "Aborted, the document was empty.")
+			return nil
+		}
+
+		edited, err := parseDocument(text)
+		if err == nil {
+			changes, err = diffDocuments(task, original, edited, store)
+		}
+		if err == nil {
+			break
+		}
+		text = append([]byte("# Error: "+err.Error()+"\n# Fix the task below and save, or empty the file to abort.\n"), text...)
+	}
+
+	structured := out.Structured() || len(out.Columns) > 0
+	if !changes.changed && changes.move == nil {
+		if structured {
+			return resultTable(store.Names(), out.DateFormat).WriteOne(out, TaskResult{Task: task})
+		}
+		fmt.Println("No changes.")
+		return nil
+	}
+
+	queued, sent := false, false
+	if changes.changed {
+		err := client.UpdateTask(ctx, task.ID, changes.update)
+		switch {
+		case errors.Is(err, queue.ErrQueued):
+			queued = true
+		case err != nil:
+			return fmt.Errorf("failed to update task: %w", err)
+		default:
+			sent = true
+		}
+	}
+	if changes.move != nil {
+		err := client.MoveTask(ctx, task.ID, *changes.move)
+		switch {
+		case errors.Is(err, queue.ErrQueued):
+			queued = true
+		case err != nil:
+			if sent {
+				refreshCache(ctx, client, store)
+			}
+			return fmt.Errorf("failed to move task: %w", err)
+		default:
+			sent = true
+		}
+	}
+	result := TaskResult{Task: task, Queued: queued}
+	if changes.update.Content != nil {
+		result.Task.Content = *changes.update.Content
+	}
+	if sent && refreshCache(ctx, client, store) && !queued {
+		// Prefer the synced copy, which has every change and the fields Todoist fills in
+		if synced, ok := store.Task(task.ID); ok {
+			result.Task = synced
+		}
+	}
+
+	if structured {
+		return resultTable(store.Names(), out.DateFormat).WriteOne(out, result)
+	}
+	printResult(result, "Updated")
+	return nil
+}
+
+func documentFromTask(task todoist.Task, store *cache.Cache) taskDocument {
+	doc := taskDocument{
+		Content:     strings.TrimSpace(task.Content),
+		Description: strings.TrimSpace(task.Description),
+	}
+	doc.Labels = task.Labels
+	doc.Priority = FormatPriority(task.Priority)
+
+	if project, err := store.FindProject(task.ProjectID); err == nil {
+		doc.Project = project.Name
+	} else {
+		doc.Project = task.ProjectID
+	}
+	if task.SectionID != "" {
+		if section, err := store.FindSection("", task.SectionID); err == nil {
+			doc.Section = section.Name
+		} else {
+			doc.Section = task.SectionID
+		}
+	}
+
+	if task.Due != nil {
+		// Recurring tasks keep their recurrence text, others show the concrete date
+		if recurring, _ := task.Due["is_recurring"].(bool); recurring {
+			doc.Due, _ = task.Due["string"].(string)
+		} else {
+			doc.Due, _ = task.Due["date"].(string)
+		}
+	}
+	if task.Deadline != nil {
+		doc.Deadline, _ = task.Deadline["date"].(string)
+	}
+	if task.Duration != nil {
+		amount, _ := task.Duration["amount"].(float64)
+		unit, _ := task.Duration["unit"].(string)
+		if amount > 0 {
+			doc.Duration = FormatDuration(int(amount), unit)
+		}
+	}
+	return doc
+}
+
+func (d taskDocument) render() []byte {
+	var b bytes.Buffer
+	b.WriteString(frontMatterDelimiter + "\n")
+	header, _ := yaml.Marshal(d.taskFrontMatter)
+	b.Write(header)
+	b.WriteString(frontMatterDelimiter + "\n")
+	b.WriteString(d.Content + "\n")
+	if d.Description != "" {
+		b.WriteString("\n" + d.Description + "\n")
+	}
+	return b.Bytes()
+}
+
+// stripComments removes the '#' lines above the front matter, where errors are shown
+func stripComments(data []byte) []byte {
+	for bytes.HasPrefix(data, []byte("#")) {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			return nil
+		}
+		data = data[i+1:]
+	}
+	return data
+}
+
+func parseDocument(data []byte) (taskDocument, error) {
+	var doc taskDocument
+
+	text := strings.TrimLeft(string(data), "\n")
+	rest, ok := strings.CutPrefix(text, frontMatterDelimiter+"\n")
+	if !ok {
+		return doc, fmt.Errorf("the document must start with a %s line", frontMatterDelimiter)
+	}
+	header, body, ok := strings.Cut(rest, "\n"+frontMatterDelimiter+"\n")
+	if !ok {
+		header, ok = strings.CutSuffix(rest, "\n"+frontMatterDelimiter)
+		if !ok {
+			return doc, fmt.Errorf("the front matter must end with a %s line", frontMatterDelimiter)
+		}
+		body = ""
+	}
+
+	decoder := yaml.NewDecoder(strings.NewReader(header))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc.taskFrontMatter); err != nil && !errors.Is(err, io.EOF) {
+		return doc, fmt.Errorf("invalid front matter: %w", err)
+	}
+
+	body = strings.TrimLeft(body, "\n")
+	content, description, _ := strings.Cut(body, "\n")
+	doc.Content = strings.TrimSpace(content)
+	doc.Description = strings.TrimSpace(description)
+	if doc.Content == "" {
+		return doc, fmt.Errorf("the task content, the first line after the front matter, must not be empty")
+	}
+	return doc, nil
+}
+
+func diffDocuments(task todoist.Task, original, edited taskDocument, store *cache.Cache) (*taskChanges, error) {
+	changes := &taskChanges{}
+	update := &changes.update
+
+	if edited.Content != original.Content {
+		update.Content = &edited.Content
+	}
+	if edited.Description != original.Description {
+		update.Description = &edited.Description
+	}
+	if !slices.Equal(edited.Labels, original.Labels) {
+		labels := edited.Labels
+		if labels == nil {
+			labels = []string{}
+		}
+		update.Labels = &labels
+	}
+	if edited.Priority != original.Priority {
+		priority := 1
+		if edited.Priority != "" {
+			var err error
+			if priority, err = ParsePriority(edited.Priority); err != nil {
+				return nil, err
+			}
+		}
+		update.Priority = &priority
+	}
+	if edited.Due != original.Due {
+		// An empty due string clears the due date
+		update.DueString = &edited.Due
+	}
+	if edited.Deadline != original.Deadline {
+		deadline := edited.Deadline
+		if deadline != "" {
+			var err error
+			if deadline, err = ParseDate(deadline); err != nil {
+				return nil, err
+			}
+		}
+		update.DeadlineDate = &deadline
+	}
+	if edited.Duration != original.Duration {
+		amount, unit := 0, ""
+		if edited.Duration != "" {
+			var err error
+			if amount, unit, err = ParseDuration(edited.Duration); err != nil {
+				return nil, err
+			}
+		}
+		update.Duration = &amount
+		if unit != "" {
+			update.DurationUnit = &unit
+		}
+	}
+	changes.changed = *update != todoist.UpdateTaskOptions{}
+
+	projectChanged := edited.Project != original.Project
+	sectionChanged := edited.Section != original.Section
+	if !projectChanged && !sectionChanged {
+		return changes, nil
+	}
+
+	projectID := task.ProjectID
+	if projectChanged {
+		if edited.Project == "" {
+			return nil, fmt.Errorf("project must not be empty")
+		}
+		project, err := store.FindProject(edited.Project)
+		if err != nil {
+			return nil, err
+		}
+		projectID = project.ID
+	}
+
+	if edited.Section != "" && (sectionChanged || projectChanged) {
+		section, err := store.FindSection(projectID, edited.Section)
+		if err != nil {
+			return nil, err
+		}
+		if section.ProjectID != projectID {
+			return nil, fmt.Errorf("section %q is not in project %q", edited.Section, edited.Project)
+		}
+		changes.move = &todoist.MoveTaskOptions{SectionID: section.ID}
+	} else {
+		changes.move = &todoist.MoveTaskOptions{ProjectID: projectID}
+	}
+	return changes, nil
+}
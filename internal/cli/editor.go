@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// OpenEditor opens path in $VISUAL, $EDITOR or vi and waits for it to exit
+func OpenEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// Run through the shell so editors configured with arguments, like "code --wait", work
+	edit := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	edit.Stdin, edit.Stdout, edit.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := edit.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+	return nil
+}
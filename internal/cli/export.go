@@ -0,0 +1,361 @@
+package cli
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// Export formats
+const (
+	ExportMarkdown = "markdown"
+	ExportTodoTxt  = "todotxt"
+	ExportOrg      = "org"
+	ExportICS      = "ics"
+)
+
+// ExportFormats lists the formats Export writes
+var ExportFormats = []string{ExportMarkdown, ExportTodoTxt, ExportOrg, ExportICS}
+
+// ExportOptions selects the exported tasks and the format they are written in
+type ExportOptions struct {
+	Format string
+	Cache  cache.Options
+	Filter query.Filter
+	// Project and Section are names or IDs, resolved into Filter
+	Project string
+	Section string
+}
+
+// ValidateExportFormat checks that format is one of ExportFormats
+func ValidateExportFormat(format string) error {
+	if !slices.Contains(ExportFormats, format) {
+		return fmt.Errorf("unknown export format %q, use one of: %s", format, strings.Join(ExportFormats, ", "))
+	}
+	return nil
+}
+
+// exportGroup is the tasks of one project outside any section, or of one section
+type exportGroup struct {
+	// Project is the path of the project's name
+	Project string
+	Section string
+	Tasks   []*query.Node
+}
+
+// Export writes the open tasks matching the options to w, grouped by project and section with subtasks nested
+func Export(ctx context.Context, client todoist.Client, opts ExportOptions, w io.Writer) error {
+	if err := ValidateExportFormat(opts.Format); err != nil {
+		return err
+	}
+	store, err := cache.Fetch(ctx, client, opts.Cache)
+	if err != nil {
+		return fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+	filter := opts.Filter
+	list := ListOptions{Cache: opts.Cache, Project: opts.Project, Section: opts.Section}
+	if err := resolveFilter(ctx, client, store, list, &filter); err != nil {
+		return err
+	}
+
+	open := openTasks(store, filter)
+
+	buf := bufio.NewWriter(w)
+	switch opts.Format {
+	case ExportMarkdown:
+		writeMarkdown(buf, exportGroups(store, open))
+	case ExportTodoTxt:
+		writeTodoTxt(buf, exportGroups(store, open), store.Names())
+	case ExportOrg:
+		writeOrg(buf, exportGroups(store, open))
+	case ExportICS:
+		if err := WriteICS(buf, open, store.Names().Projects, ICSOptions{Name: "Todoist"}, time.Now()); err != nil {
+			return err
+		}
+	}
+	return buf.Flush()
+}
+
+// openTasks returns the cached open tasks matching filter in child order
+func openTasks(store *cache.Cache, filter query.Filter) []todoist.Task {
+	tasks, _ := store.Snapshot()
+	var open []todoist.Task
+	for _, t := range query.Apply(tasks, filter, time.Now()) {
+		if !t.Checked {
+			open = append(open, t)
+		}
+	}
+	query.Sort(open, []query.SortKey{{Field: "order"}}, nil)
+	return open
+}
+
+// exportGroups nests the tasks under their parents and groups them by project and section,
+// in the order of Todoist's sidebar
+func exportGroups(store *cache.Cache, tasks []todoist.Task) []exportGroup {
+	roots := query.BuildTree(tasks)
+
+	sections := slices.Clone(store.Sections)
+	slices.SortStableFunc(sections, func(a, b todoist.Section) int { return cmp.Compare(a.SectionOrder, b.SectionOrder) })
+
+	var groups []exportGroup
+	for _, project := range projectsInOrder(store.Projects) {
+		path := projectPath(store, project.ID)
+		groups = append(groups, exportGroup{Project: path, Tasks: nodesIn(roots, project.ID, "")})
+		for _, s := range sections {
+			if s.ProjectID == project.ID {
+				groups = append(groups, exportGroup{Project: path, Section: s.Name, Tasks: nodesIn(roots, project.ID, s.ID)})
+			}
+		}
+	}
+	return slices.DeleteFunc(groups, func(g exportGroup) bool { return len(g.Tasks) == 0 })
+}
+
+// projectsInOrder returns the projects depth first, siblings in child order
+func projectsInOrder(projects []todoist.Project) []todoist.Project {
+	children := make(map[string][]todoist.Project)
+	ids := make(map[string]bool)
+	for _, p := range projects {
+		ids[p.ID] = true
+	}
+	for _, p := range projects {
+		parent := p.ParentID
+		if !ids[parent] {
+			parent = ""
+		}
+		children[parent] = append(children[parent], p)
+	}
+
+	var ordered []todoist.Project
+	var visit func(parentID string)
+	visit = func(parentID string) {
+		siblings := children[parentID]
+		slices.SortStableFunc(siblings, func(a, b todoist.Project) int {
+			// The Inbox always comes first
+			if a.InboxProject != b.InboxProject {
+				if a.InboxProject {
+					return -1
+				}
+				return 1
+			}
+			return cmp.Compare(a.ChildOrder, b.ChildOrder)
+		})
+		for _, p := range siblings {
+			ordered = append(ordered, p)
+			visit(p.ID)
+		}
+	}
+	visit("")
+	return ordered
+}
+
+// nodesIn returns the top level tasks of a project and section, "" for those outside any section
+func nodesIn(roots []*query.Node, projectID, sectionID string) []*query.Node {
+	var nodes []*query.Node
+	for _, n := range roots {
+		if n.Task.ProjectID == projectID && n.Task.SectionID == sectionID {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// walkTasks calls fn for every task depth first, with how deep it is nested
+func walkTasks(nodes []*query.Node, depth int, fn func(n *query.Node, depth int)) {
+	for _, n := range nodes {
+		fn(n, depth)
+		walkTasks(n.Children, depth+1, fn)
+	}
+}
+
+// writeMarkdown writes a checklist per project and section, with subtasks as nested items
+// and descriptions indented below their task
+func writeMarkdown(w io.Writer, groups []exportGroup) {
+	project := ""
+	for i, g := range groups {
+		if g.Project != project {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "# %s\n\n", g.Project)
+			project = g.Project
+		}
+		if g.Section != "" {
+			fmt.Fprintf(w, "## %s\n\n", g.Section)
+		}
+		walkTasks(g.Tasks, 0, func(n *query.Node, depth int) {
+			indent := strings.Repeat("  ", depth)
+			fmt.Fprintf(w, "%s- [ ] %s", indent, singleLine(n.Task.Content))
+			if details := taskDetails(n.Task); len(details) > 0 {
+				fmt.Fprintf(w, " (%s)", strings.Join(details, ", "))
+			}
+			fmt.Fprintln(w)
+			if n.Task.Description != "" {
+				for _, line := range strings.Split(n.Task.Description, "\n") {
+					fmt.Fprintf(w, "%s  %s\n", indent, line)
+				}
+			}
+		})
+		if i < len(groups)-1 && groups[i+1].Project == project {
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+// taskDetails lists the priority, dates, duration and labels of a task for Markdown
+func taskDetails(t todoist.Task) []string {
+	var details []string
+	if t.Priority > 1 {
+		details = append(details, FormatPriority(t.Priority))
+	}
+	if due := formatDue(t, "2006-01-02"); due != "" {
+		details = append(details, "due "+due)
+	}
+	if deadline := dateField(t.Deadline); deadline != "" {
+		details = append(details, "deadline "+deadline)
+	}
+	if duration := formatTaskDuration(t); duration != "" {
+		details = append(details, duration)
+	}
+	for _, l := range t.Labels {
+		details = append(details, "@"+l)
+	}
+	return details
+}
+
+// writeTodoTxt writes one line per task with its priority, creation date, +project, @labels and due: date.
+// Parents get an id: tag that their subtasks refer to with p:, so the file imports back with its hierarchy.
+func writeTodoTxt(w io.Writer, groups []exportGroup, names cache.Names) {
+	for _, g := range groups {
+		walkTasks(g.Tasks, 0, func(n *query.Node, depth int) {
+			t := n.Task
+			var words []string
+			if t.Priority > 1 {
+				words = append(words, fmt.Sprintf("(%c)", 'A'+4-t.Priority))
+			}
+			if added, err := time.Parse(time.RFC3339, t.AddedAt); err == nil {
+				words = append(words, added.Local().Format("2006-01-02"))
+			}
+			words = append(words, singleLine(t.Content))
+			if project := names.Projects[t.ProjectID]; project != "" {
+				words = append(words, "+"+todoTxtTag(project))
+			}
+			for _, l := range t.Labels {
+				words = append(words, "@"+todoTxtTag(l))
+			}
+			if due, _, ok := taskDue(t); ok {
+				words = append(words, "due:"+due.Local().Format("2006-01-02"))
+			}
+			if len(n.Children) > 0 {
+				words = append(words, "id:"+t.ID)
+			}
+			if depth > 0 {
+				words = append(words, "p:"+t.ParentID)
+			}
+			fmt.Fprintln(w, strings.Join(words, " "))
+		})
+	}
+}
+
+// singleLine joins the lines of s, for formats with one line per task
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// todoTxtTag makes a name usable as a todo.txt project or context, which cannot contain spaces
+func todoTxtTag(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+// writeOrg writes a heading per project and section with the tasks as TODO headings below them,
+// scheduled at their due date and with their deadline, duration as effort and labels as tags
+func writeOrg(w io.Writer, groups []exportGroup) {
+	project := ""
+	for _, g := range groups {
+		if g.Project != project {
+			fmt.Fprintf(w, "* %s\n", g.Project)
+			project = g.Project
+		}
+		level := 2
+		if g.Section != "" {
+			fmt.Fprintf(w, "** %s\n", g.Section)
+			level = 3
+		}
+		walkTasks(g.Tasks, level, func(n *query.Node, depth int) {
+			writeOrgTask(w, n.Task, depth)
+		})
+	}
+}
+
+func writeOrgTask(w io.Writer, t todoist.Task, level int) {
+	heading := strings.Repeat("*", level) + " TODO "
+	if t.Priority > 1 {
+		heading += fmt.Sprintf("[#%c] ", 'A'+4-t.Priority)
+	}
+	heading += singleLine(t.Content)
+	if len(t.Labels) > 0 {
+		tags := make([]string, len(t.Labels))
+		for i, l := range t.Labels {
+			tags[i] = orgTag(l)
+		}
+		heading += " :" + strings.Join(tags, ":") + ":"
+	}
+	fmt.Fprintln(w, heading)
+
+	indent := strings.Repeat(" ", level+1)
+	var planning []string
+	if due, timed, ok := taskDue(t); ok {
+		planning = append(planning, "SCHEDULED: "+orgTimestamp(due.Local(), timed, plannedMinutes(t)))
+	}
+	if deadline, err := time.ParseInLocation("2006-01-02", dateField(t.Deadline), time.Local); err == nil {
+		planning = append(planning, "DEADLINE: "+orgTimestamp(deadline, false, 0))
+	}
+	if len(planning) > 0 {
+		fmt.Fprintln(w, indent+strings.Join(planning, " "))
+	}
+
+	fmt.Fprintln(w, indent+":PROPERTIES:")
+	fmt.Fprintln(w, indent+":TODOIST_ID: "+t.ID)
+	if minutes := plannedMinutes(t); minutes > 0 {
+		fmt.Fprintf(w, "%s:Effort: %d:%02d\n", indent, minutes/60, minutes%60)
+	}
+	fmt.Fprintln(w, indent+":END:")
+
+	if t.Description != "" {
+		for _, line := range strings.Split(t.Description, "\n") {
+			// Indenting keeps lines starting with * from becoming headings
+			fmt.Fprintln(w, strings.TrimRight(indent+line, " "))
+		}
+	}
+}
+
+// orgTimestamp formats an active Org timestamp, with a time range for timed tasks with a duration
+func orgTimestamp(t time.Time, timed bool, minutes int) string {
+	if !timed {
+		return t.Format("<2006-01-02 Mon>")
+	}
+	stamp := t.Format("<2006-01-02 Mon 15:04")
+	if end := t.Add(time.Duration(minutes) * time.Minute); minutes > 0 && end.YearDay() == t.YearDay() {
+		stamp += end.Format("-15:04")
+	}
+	return stamp + ">"
+}
+
+// orgTag makes a label usable as an Org tag, which only allows letters, digits, _, @, # and %
+func orgTag(label string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '@' || r == '#' || r == '%' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, label)
+}
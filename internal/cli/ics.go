@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// icsLineLength is the maximum length of an iCalendar content line in bytes, longer lines are folded
+const icsLineLength = 75
+
+const (
+	icsDate     = "20060102"
+	icsDateTime = "20060102T150405Z"
+)
+
+// taskURL links to a task in the Todoist web app
+const taskURL = "https://app.todoist.com/app/task/"
+
+// ICSOptions controls how tasks become calendar entries
+type ICSOptions struct {
+	// Name is shown by calendar apps as the name of the calendar
+	Name string
+	// Events makes a calendar of events only: every task with a due date is an event, and so is every deadline.
+	// Otherwise only tasks with a duration are events and the rest are to-dos.
+	Events bool
+}
+
+// icsPriorities maps the API's priorities, 4 being urgent, to iCalendar's where 1 is the highest
+var icsPriorities = map[int]int{4: 1, 3: 3, 2: 5}
+
+// WriteICS writes tasks as an iCalendar document. Timed tasks become events spanning their duration,
+// and tasks due on a date all-day events, or VTODOs due at their due date or deadline unless opts.Events is set.
+func WriteICS(w io.Writer, tasks []todoist.Task, names map[string]string, opts ICSOptions, now time.Time) error {
+	ics := &icsWriter{w: w, names: names, stamp: now.UTC().Format(icsDateTime)}
+	ics.line("BEGIN:VCALENDAR")
+	ics.line("VERSION:2.0")
+	ics.line("PRODID:-//todoist-cli//EN")
+	ics.line("CALSCALE:GREGORIAN")
+	if opts.Name != "" {
+		ics.property("X-WR-CALNAME", opts.Name)
+	}
+	for _, t := range tasks {
+		due, timed, hasDue := taskDue(t)
+		deadline, deadlineErr := time.ParseInLocation("2006-01-02", dateField(t.Deadline), time.Local)
+
+		switch {
+		case hasDue && (opts.Events || plannedMinutes(t) > 0):
+			ics.begin("VEVENT", t, t.ID, t.Content)
+			ics.line("DTSTART" + icsTime(due, timed))
+			if plannedMinutes(t) > 0 {
+				ics.line("DURATION:" + icsDuration(t))
+			}
+			ics.line("END:VEVENT")
+		case opts.Events:
+		default:
+			ics.begin("VTODO", t, t.ID, t.Content)
+			if hasDue {
+				ics.line("DUE" + icsTime(due, timed))
+			} else if deadlineErr == nil {
+				ics.line("DUE" + icsTime(deadline, false))
+			}
+			ics.line("STATUS:NEEDS-ACTION")
+			ics.line("END:VTODO")
+		}
+
+		if opts.Events && deadlineErr == nil {
+			ics.begin("VEVENT", t, t.ID+"-deadline", "Deadline: "+t.Content)
+			ics.line("DTSTART" + icsTime(deadline, false))
+			ics.line("END:VEVENT")
+		}
+	}
+	ics.line("END:VCALENDAR")
+	return ics.err
+}
+
+// taskDue returns when t is due and whether that includes a time of day
+func taskDue(t todoist.Task) (time.Time, bool, bool) {
+	due, ok := query.DueTime(t)
+	date, _ := t.Due["date"].(string)
+	return due, len(date) > len("2006-01-02"), ok
+}
+
+// dateField returns the date of a due or deadline field
+func dateField(field map[string]any) string {
+	date, _ := field["date"].(string)
+	return date
+}
+
+// icsTime formats the value of a date property, with the parameter marking dates without a time
+func icsTime(t time.Time, timed bool) string {
+	if !timed {
+		return ";VALUE=DATE:" + t.Format(icsDate)
+	}
+	return ":" + t.UTC().Format(icsDateTime)
+}
+
+// icsDuration formats the task's duration as an iCalendar duration like PT1H30M or P2D
+func icsDuration(t todoist.Task) string {
+	amount, _ := t.Duration["amount"].(float64)
+	if unit, _ := t.Duration["unit"].(string); unit == "day" {
+		return fmt.Sprintf("P%dD", int(amount))
+	}
+	hours, minutes := int(amount)/60, int(amount)%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("PT%dM", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("PT%dH", hours)
+	default:
+		return fmt.Sprintf("PT%dH%dM", hours, minutes)
+	}
+}
+
+// icsEscape escapes a text value
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icsWriter writes content lines ending in CRLF, folding long ones, and keeps the first error
+type icsWriter struct {
+	w io.Writer
+	// names maps project IDs to names
+	names map[string]string
+	stamp string
+	err   error
+}
+
+// begin starts a component for the task with the properties events and to-dos share
+func (ics *icsWriter) begin(component string, t todoist.Task, uid, summary string) {
+	ics.line("BEGIN:" + component)
+	ics.line("UID:" + uid + "@todoist.com")
+	ics.line("DTSTAMP:" + ics.stamp)
+	ics.property("SUMMARY", summary)
+	if t.Description != "" {
+		ics.property("DESCRIPTION", t.Description)
+	}
+	ics.line("URL:" + taskURL + t.ID)
+
+	// The project and labels become categories
+	var categories []string
+	if project := ics.names[t.ProjectID]; project != "" {
+		categories = append(categories, icsEscape(project))
+	}
+	for _, l := range t.Labels {
+		categories = append(categories, icsEscape(l))
+	}
+	if len(categories) > 0 {
+		ics.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+	if p, ok := icsPriorities[t.Priority]; ok {
+		ics.line(fmt.Sprintf("PRIORITY:%d", p))
+	}
+}
+
+// property writes a property with an escaped text value
+func (ics *icsWriter) property(name, value string) {
+	ics.line(name + ":" + icsEscape(value))
+}
+
+func (ics *icsWriter) line(s string) {
+	if ics.err != nil {
+		return
+	}
+	var b strings.Builder
+	limit := icsLineLength
+	for len(s) > limit {
+		// Fold between runes, continuation lines start with a space
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = icsLineLength - 1
+	}
+	b.WriteString(s + "\r\n")
+	_, ics.err = io.WriteString(ics.w, b.String())
+}
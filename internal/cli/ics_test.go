@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestWriteICS(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	names := map[string]string{"p1": "Inbox", "p2": "Work, Q4"}
+
+	tests := []struct {
+		name  string
+		opts  ICSOptions
+		tasks []todoist.Task
+	}{
+		{
+			name: "timed_duration",
+			tasks: []todoist.Task{
+				{
+					ID: "1", Content: "Standup", ProjectID: "p2", Labels: []string{"meeting"}, Priority: 4,
+					Due:      map[string]any{"date": "2026-10-20T09:00:00Z"},
+					Duration: map[string]any{"amount": float64(90), "unit": "minute"},
+				},
+				{
+					ID: "2", Content: "Quick call", ProjectID: "p1", Priority: 2,
+					Due:      map[string]any{"date": "2026-10-20T13:15:00Z"},
+					Duration: map[string]any{"amount": float64(15), "unit": "minute"},
+				},
+				{
+					ID: "3", Content: "Offsite", ProjectID: "p2",
+					Due:      map[string]any{"date": "2026-10-21"},
+					Duration: map[string]any{"amount": float64(2), "unit": "day"},
+				},
+			},
+		},
+		{
+			name: "all_day",
+			opts: ICSOptions{Name: "Todoist", Events: true},
+			tasks: []todoist.Task{
+				{ID: "1", Content: "Pay rent", ProjectID: "p1", Due: map[string]any{"date": "2026-11-01"}},
+				{ID: "2", Content: "Call mom", ProjectID: "p1", Due: map[string]any{"date": "2026-10-20T18:00:00Z"}},
+				{ID: "3", Content: "Someday", ProjectID: "p1"},
+			},
+		},
+		{
+			name: "todos",
+			tasks: []todoist.Task{
+				{ID: "1", Content: "Pay rent", ProjectID: "p1", Priority: 3, Due: map[string]any{"date": "2026-11-01"}},
+				{ID: "2", Content: "Call mom", ProjectID: "p1", Due: map[string]any{"date": "2026-10-20T18:00:00Z"}},
+				{ID: "3", Content: "Report", ProjectID: "p2", Deadline: map[string]any{"date": "2026-10-30"}},
+				{ID: "4", Content: "Someday", ProjectID: "p1"},
+			},
+		},
+		{
+			name: "deadline",
+			opts: ICSOptions{Events: true},
+			tasks: []todoist.Task{
+				{
+					ID: "1", Content: "Draft report", ProjectID: "p2",
+					Due:      map[string]any{"date": "2026-10-26"},
+					Deadline: map[string]any{"date": "2026-10-30"},
+				},
+				{ID: "2", Content: "Tax return", ProjectID: "p1", Deadline: map[string]any{"date": "2026-11-15"}},
+				{ID: "3", Content: "No dates", ProjectID: "p1"},
+			},
+		},
+		{
+			name: "folding",
+			tasks: []todoist.Task{
+				{
+					ID: "1", ProjectID: "p1", Due: map[string]any{"date": "2026-10-20"},
+					// The folds fall inside the two byte å, the four byte emoji and the three byte kanji
+					Content:     strings.Repeat("a", 66) + "å" + strings.Repeat("b", 70) + "😀😀 done",
+					Description: "x" + strings.Repeat("日本語", 30),
+				},
+			},
+		},
+		{
+			name: "escaping",
+			opts: ICSOptions{Name: `Work; "Q4", mostly`},
+			tasks: []todoist.Task{
+				{
+					ID: "1", ProjectID: "p2", Labels: []string{"a,b", "c;d"}, Due: map[string]any{"date": "2026-10-20"},
+					Content:     `Buy milk, eggs; bread \ more`,
+					Description: "First line\nsecond line\r\nthird line",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteICS(&buf, tt.tasks, names, tt.opts, now); err != nil {
+				t.Fatal(err)
+			}
+			checkICSLines(t, buf.Bytes())
+
+			golden := filepath.Join("testdata", tt.name+".ics")
+			if *update {
+				if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("WriteICS() does not match %s, run go test -update to see the difference:\n%s", golden, buf.String())
+			}
+		})
+	}
+}
+
+// checkICSLines checks that every content line ends in CRLF, is at most icsLineLength bytes and valid UTF-8 on its own
+func checkICSLines(t *testing.T, data []byte) {
+	t.Helper()
+	if !bytes.HasSuffix(data, []byte("\r\n")) {
+		t.Fatal("the document does not end in CRLF")
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(string(data), "\r\n"), "\r\n") {
+		if strings.Contains(line, "\n") {
+			t.Errorf("line %d has a bare line feed: %q", i+1, line)
+		}
+		if len(line) > icsLineLength {
+			t.Errorf("line %d is %d bytes long: %q", i+1, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d splits a character: %q", i+1, line)
+		}
+	}
+}
+
+func TestICSFoldingRoundTrip(t *testing.T) {
+	content := strings.Repeat("ä", 40) + strings.Repeat("x", 33) + "😀" + strings.Repeat("ö", 100)
+	var buf bytes.Buffer
+	task := todoist.Task{ID: "1", Content: content, Due: map[string]any{"date": "2026-10-20"}}
+	if err := WriteICS(&buf, []todoist.Task{task}, nil, ICSOptions{}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	checkICSLines(t, buf.Bytes())
+
+	unfolded := strings.ReplaceAll(buf.String(), "\r\n ", "")
+	if !strings.Contains(unfolded, "\r\nSUMMARY:"+content+"\r\n") {
+		t.Errorf("unfolding does not restore the summary:\n%s", unfolded)
+	}
+}
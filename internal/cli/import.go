@@ -0,0 +1,364 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// importBatchSize is the maximum number of commands Todoist accepts in one Sync request
+const importBatchSize = 100
+
+// ImportOptions controls where imported tasks go and what may be created for them
+type ImportOptions struct {
+	// Project is the name or ID of the project for tasks that name none, the Inbox if empty
+	Project string
+	// CreateMissing creates projects, sections and labels that do not exist yet instead of failing
+	CreateMissing bool
+	// DryRun prints the tasks and what would be created without changing anything
+	DryRun bool
+	Cache  cache.Options
+}
+
+var importTable = output.Table[ImportTask]{
+	Default: []string{"line", "content", "project", "section", "labels", "priority", "due"},
+	Columns: []output.Column[ImportTask]{
+		{Name: "line", Header: "Line", Value: func(t ImportTask) string { return strconv.Itoa(t.Line) }},
+		{Name: "content", Header: "Content", Shrink: true, Value: func(t ImportTask) string {
+			return strings.Repeat("  ", t.Depth) + t.Content
+		}},
+		{Name: "description", Header: "Description", Shrink: true, Value: func(t ImportTask) string { return t.Description }},
+		{Name: "project", Header: "Project", Value: func(t ImportTask) string { return strings.Join(t.Project, "/") }},
+		{Name: "section", Header: "Section", Value: func(t ImportTask) string { return t.Section }},
+		{Name: "labels", Header: "Labels", Value: func(t ImportTask) string { return strings.Join(t.Labels, ", ") }},
+		{Name: "priority", Header: "Priority", Value: func(t ImportTask) string {
+			if t.Priority == 0 {
+				return ""
+			}
+			return FormatPriority(t.Priority)
+		}},
+		{Name: "due", Header: "Due", Value: func(t ImportTask) string {
+			return cmp.Or(t.DueDatetime, t.DueDate, t.DueString)
+		}},
+		{Name: "deadline", Header: "Deadline", Value: func(t ImportTask) string { return t.DeadlineDate }},
+		{Name: "duration", Header: "Duration", Value: func(t ImportTask) string {
+			if t.Duration == 0 {
+				return ""
+			}
+			return FormatDuration(t.Duration, t.DurationUnit)
+		}},
+		{Name: "key", Header: "Key", Value: func(t ImportTask) string { return t.Key }},
+		{Name: "parent", Header: "Parent", Value: func(t ImportTask) string { return t.Parent }},
+	},
+}
+
+// Import creates the tasks read by ParseImport, sending them as Sync commands in batches.
+// Missing projects, sections and labels are created ahead of the tasks using them when CreateMissing is set.
+func Import(ctx context.Context, client todoist.Client, tasks []ImportTask, opts ImportOptions, out output.Options) error {
+	if opts.Cache.Offline && !opts.DryRun {
+		return fmt.Errorf("importing needs Todoist, use --dry-run to preview offline")
+	}
+	store, err := cache.Fetch(ctx, client, opts.Cache)
+	if err != nil {
+		return fmt.Errorf("failed to fetch projects: %w", err)
+	}
+
+	if opts.Project != "" {
+		for i := range tasks {
+			if tasks[i].Depth == 0 && len(tasks[i].Project) == 0 {
+				tasks[i].Project = []string{opts.Project}
+			}
+		}
+	}
+	plan, err := planImport(store, tasks, opts.CreateMissing)
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		if err := importTable.Write(out, tasks); err != nil {
+			return err
+		}
+		if !out.Structured() {
+			fmt.Println(plan.summary("Would import", len(tasks)))
+		}
+		return nil
+	}
+
+	failed, sent, err := syncBatches(ctx, client, plan.commands)
+	imported := 0
+	for _, cmd := range plan.commands[:sent] {
+		if line, ok := plan.lines[cmd.UUID]; ok {
+			if msg, bad := failed[cmd.UUID]; bad {
+				fmt.Fprintf(os.Stderr, "line %d: %s\n", line, msg)
+			} else {
+				imported++
+			}
+		}
+	}
+	if imported > 0 {
+		fmt.Println(plan.summary("Imported", imported))
+		if err := store.Refresh(ctx, client); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to refresh cache:", err)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("import stopped after %d of %d tasks: %w", imported, len(tasks), err)
+	}
+	if imported < len(tasks) {
+		return fmt.Errorf("%d of %d tasks failed to import", len(tasks)-imported, len(tasks))
+	}
+	return nil
+}
+
+// importPlan is the Sync commands creating the imported tasks and everything they need
+type importPlan struct {
+	commands []todoist.Command
+	// lines maps the UUID of each item_add command to the line of its task
+	lines map[string]int
+	// projects, sections and labels are the names of those created by the plan
+	projects []string
+	sections []string
+	labels   []string
+}
+
+// summary describes the plan in one line, starting with verb and the number of tasks
+func (p *importPlan) summary(verb string, tasks int) string {
+	line := verb + " 1 task"
+	if tasks != 1 {
+		line = fmt.Sprintf("%s %d tasks", verb, tasks)
+	}
+	var created []string
+	for _, c := range []struct {
+		kind  string
+		names []string
+	}{{"projects", p.projects}, {"sections", p.sections}, {"labels", p.labels}} {
+		if len(c.names) > 0 {
+			created = append(created, c.kind+" "+strings.Join(c.names, ", "))
+		}
+	}
+	if len(created) > 0 {
+		line += ", creating " + strings.Join(created, "; ")
+	}
+	return line
+}
+
+// importPlanner resolves the names of imported tasks against the cache, remembering what it already resolved or created
+type importPlanner struct {
+	store  *cache.Cache
+	create bool
+	plan   *importPlan
+	// projects maps lower case project paths, and sections a project ID with a lower case name, to real or temporary IDs
+	projects map[string]string
+	sections map[string]string
+	// labels holds the lower case names of existing and created labels
+	labels  map[string]bool
+	missing []string
+}
+
+// planImport builds the commands creating tasks, failing on missing names unless create is set
+func planImport(store *cache.Cache, tasks []ImportTask, create bool) (*importPlan, error) {
+	p := &importPlanner{
+		store:    store,
+		create:   create,
+		plan:     &importPlan{lines: make(map[string]int)},
+		projects: make(map[string]string),
+		sections: make(map[string]string),
+		labels:   make(map[string]bool),
+	}
+	// Shared labels only show up on tasks, so those count as existing too
+	for _, l := range store.Labels {
+		p.labels[strings.ToLower(l.Name)] = true
+	}
+	cached, _ := store.Snapshot()
+	for _, t := range cached {
+		for _, l := range t.Labels {
+			p.labels[strings.ToLower(l)] = true
+		}
+	}
+
+	taskIDs := make(map[string]string)
+	for _, t := range tasks {
+		options := t.CreateTaskOptions
+		if t.Parent != "" {
+			options.ParentID = taskIDs[t.Parent]
+		} else {
+			projectID, ok, err := p.project(t.Project)
+			if err != nil {
+				return nil, lineError(t.Line, err)
+			}
+			options.ProjectID = projectID
+			if t.Section != "" && ok {
+				if options.SectionID, err = p.section(projectID, t.Section); err != nil {
+					return nil, lineError(t.Line, err)
+				}
+			}
+		}
+		for _, l := range t.Labels {
+			p.label(l)
+		}
+
+		cmd := todoist.AddTaskCommand(options)
+		p.plan.commands = append(p.plan.commands, cmd)
+		p.plan.lines[cmd.UUID] = t.Line
+		if t.Key != "" {
+			taskIDs[t.Key] = cmd.TempID
+		}
+	}
+
+	if len(p.missing) > 0 {
+		return nil, fmt.Errorf("%s not found, pass --create-missing to create them", strings.Join(p.missing, ", "))
+	}
+	return p.plan, nil
+}
+
+// project returns the ID of the project at path, "" for the Inbox, adding commands for the projects along it that do not exist.
+// When they may not be created they are recorded in missing and ok is false.
+func (p *importPlanner) project(path []string) (id string, ok bool, err error) {
+	for i, name := range path {
+		key := strings.ToLower(strings.Join(path[:i+1], "/"))
+		if known, seen := p.projects[key]; seen {
+			id = known
+		} else {
+			id, err = p.resolveProject(id, name, path[:i+1])
+			if err != nil {
+				return "", false, err
+			}
+			p.projects[key] = id
+		}
+		if id == "" {
+			return "", false, nil
+		}
+	}
+	return id, true, nil
+}
+
+// resolveProject returns the ID of the project name below parentID, or of the command creating it.
+// It is "" when the project is missing and may not be created.
+func (p *importPlanner) resolveProject(parentID, name string, path []string) (string, error) {
+	id, err := p.findProject(parentID, name, len(path) == 1)
+	switch {
+	case err == nil:
+		return id, nil
+	case !errors.Is(err, cache.ErrNotFound):
+		return "", err
+	case !p.create:
+		p.missing = append(p.missing, fmt.Sprintf("project %q", strings.Join(path, "/")))
+		return "", nil
+	}
+	cmd := todoist.AddProjectCommand(name, parentID)
+	p.plan.commands = append(p.plan.commands, cmd)
+	p.plan.projects = append(p.plan.projects, strings.Join(path, "/"))
+	return cmd.TempID, nil
+}
+
+// findProject looks up a top-level name or ID in the cache, or a subproject by name below parentID
+func (p *importPlanner) findProject(parentID, name string, top bool) (string, error) {
+	if top {
+		project, err := p.store.FindProject(name)
+		return project.ID, err
+	}
+	for _, project := range p.store.Projects {
+		if project.ParentID == parentID && strings.EqualFold(project.Name, name) {
+			return project.ID, nil
+		}
+	}
+	return "", fmt.Errorf("project %q: %w", name, cache.ErrNotFound)
+}
+
+// section returns the ID of the section in projectID, the Inbox if empty, adding a command when it does not exist
+func (p *importPlanner) section(projectID, name string) (string, error) {
+	if projectID == "" {
+		projectID = p.inbox()
+	}
+	key := projectID + "/" + strings.ToLower(name)
+	if id, ok := p.sections[key]; ok {
+		return id, nil
+	}
+
+	id := ""
+	if section, err := p.store.FindSection(projectID, name); err == nil && section.ProjectID == projectID {
+		id = section.ID
+	} else if !p.create {
+		p.missing = append(p.missing, fmt.Sprintf("section %q", name))
+	} else if projectID == "" {
+		return "", fmt.Errorf("cannot create section %q, the Inbox project is not cached, give the task a project", name)
+	} else {
+		cmd := todoist.AddSectionCommand(name, projectID)
+		p.plan.commands = append(p.plan.commands, cmd)
+		p.plan.sections = append(p.plan.sections, name)
+		id = cmd.TempID
+	}
+	p.sections[key] = id
+	return id, nil
+}
+
+// label adds a command creating the label if no task or personal label uses it yet
+func (p *importPlanner) label(name string) {
+	key := strings.ToLower(name)
+	if p.labels[key] {
+		return
+	}
+	p.labels[key] = true
+	if !p.create {
+		p.missing = append(p.missing, fmt.Sprintf("label %q", name))
+		return
+	}
+	p.plan.commands = append(p.plan.commands, todoist.AddLabelCommand(name))
+	p.plan.labels = append(p.plan.labels, name)
+}
+
+// inbox returns the ID of the cached Inbox project
+func (p *importPlanner) inbox() string {
+	for _, project := range p.store.Projects {
+		if project.InboxProject {
+			return project.ID
+		}
+	}
+	return ""
+}
+
+// syncBatches sends commands in batches, replacing temporary IDs from earlier batches with the real ones.
+// It returns the errors Todoist reported by command UUID and how many commands were answered,
+// stopping at the first request that fails.
+func syncBatches(ctx context.Context, client todoist.Client, commands []todoist.Command) (map[string]string, int, error) {
+	failed := make(map[string]string)
+	ids := make(map[string]string)
+	sent := 0
+	for sent < len(commands) {
+		batch := commands[sent:min(sent+importBatchSize, len(commands))]
+		for _, cmd := range batch {
+			for _, arg := range []string{"project_id", "section_id", "parent_id"} {
+				if id, ok := cmd.Args[arg].(string); ok && ids[id] != "" {
+					cmd.Args[arg] = ids[id]
+				}
+			}
+		}
+
+		resp, err := client.Sync(ctx, todoist.SyncRequest{Commands: batch})
+		if err != nil {
+			return failed, sent, err
+		}
+		maps.Copy(ids, resp.TempIDMapping)
+		for _, cmd := range batch {
+			status, ok := resp.SyncStatus[cmd.UUID]
+			if !ok {
+				failed[cmd.UUID] = "no answer from Todoist"
+			} else if msg := todoist.StatusError(status); msg != "" {
+				failed[cmd.UUID] = msg
+			}
+		}
+		sent += len(batch)
+	}
+	return failed, sent, nil
+}
@@ -0,0 +1,152 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// syncClient answers Sync requests like Todoist, mapping every temporary ID to "real-" followed by it
+type syncClient struct {
+	todoist.Client
+	// requests holds the arguments of every command as they were sent
+	requests [][]map[string]any
+	// reject are the UUIDs of commands answered with an error
+	reject map[string]bool
+	// failAt makes the request with this index fail, -1 for none
+	failAt int
+}
+
+func (c *syncClient) Sync(ctx context.Context, request todoist.SyncRequest) (*todoist.SyncResponse, error) {
+	if len(c.requests) == c.failAt {
+		return nil, errors.New("connection reset")
+	}
+	resp := &todoist.SyncResponse{SyncStatus: make(map[string]json.RawMessage), TempIDMapping: make(map[string]string)}
+	var sent []map[string]any
+	for _, cmd := range request.Commands {
+		sent = append(sent, maps.Clone(cmd.Args))
+		resp.SyncStatus[cmd.UUID] = json.RawMessage(`"ok"`)
+		if c.reject[cmd.UUID] {
+			resp.SyncStatus[cmd.UUID] = json.RawMessage(`{"error_code":15,"error":"Invalid temporary id"}`)
+		} else if cmd.TempID != "" {
+			resp.TempIDMapping[cmd.TempID] = "real-" + cmd.TempID
+		}
+	}
+	c.requests = append(c.requests, sent)
+	return resp, nil
+}
+
+// importCommands is a project followed by 150 tasks in it, so the commands span two requests.
+// The task at 101 is a subtask of the one at 100 in the same request, the last one of the task at 5 in the first.
+func importCommands() []todoist.Command {
+	project := todoist.AddProjectCommand("Work", "")
+	commands := []todoist.Command{project}
+	for i := 1; i <= 150; i++ {
+		options := todoist.CreateTaskOptions{Content: "task", ProjectID: project.TempID}
+		switch i {
+		case 101:
+			options.ParentID = commands[100].TempID
+		case 150:
+			options.ParentID = commands[5].TempID
+		}
+		commands = append(commands, todoist.AddTaskCommand(options))
+	}
+	return commands
+}
+
+func TestSyncBatches(t *testing.T) {
+	commands := importCommands()
+	client := &syncClient{reject: map[string]bool{commands[120].UUID: true}, failAt: -1}
+
+	failed, sent, err := syncBatches(context.Background(), client, commands)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != len(commands) {
+		t.Errorf("sent %d commands, want %d", sent, len(commands))
+	}
+	if len(client.requests) != 2 || len(client.requests[0]) != importBatchSize || len(client.requests[1]) != 51 {
+		t.Fatalf("sent %d requests, want 2 of 100 and 51 commands", len(client.requests))
+	}
+	if len(failed) != 1 || !strings.Contains(failed[commands[120].UUID], "Invalid temporary id") {
+		t.Errorf("failed = %v, want only the command at 120", failed)
+	}
+
+	project := commands[0].TempID
+	args := func(i int) map[string]any { return client.requests[i/importBatchSize][i%importBatchSize] }
+	tests := []struct {
+		name    string
+		command int
+		arg     string
+		want    string
+	}{
+		{"project in the same request", 1, "project_id", project},
+		{"project in the same request, last", 99, "project_id", project},
+		{"project from the earlier request", 100, "project_id", "real-" + project},
+		{"project from the earlier request, last", 150, "project_id", "real-" + project},
+		{"parent in the same request", 101, "parent_id", commands[100].TempID},
+		{"parent from the earlier request", 150, "parent_id", "real-" + commands[5].TempID},
+	}
+	for _, tt := range tests {
+		if got := args(tt.command)[tt.arg]; got != tt.want {
+			t.Errorf("%s: command %d sent %s %v, want %s", tt.name, tt.command, tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestSyncBatchesStopsAtFailedRequest(t *testing.T) {
+	commands := importCommands()
+	client := &syncClient{failAt: 1}
+
+	failed, sent, err := syncBatches(context.Background(), client, commands)
+	if err == nil {
+		t.Fatal("syncBatches() succeeded, want the error of the second request")
+	}
+	if sent != importBatchSize || len(failed) != 0 {
+		t.Errorf("syncBatches() = %v, %d, want no failures and %d answered commands", failed, sent, importBatchSize)
+	}
+}
+
+func TestPlanImportSection(t *testing.T) {
+	tasks := []ImportTask{{CreateTaskOptions: todoist.CreateTaskOptions{Content: "Buy milk"}, Line: 2, Section: "Errands"}}
+
+	tests := []struct {
+		name     string
+		projects []todoist.Project
+		create   bool
+		wantErr  string
+		wantCmds int
+	}{
+		{"missing section", []todoist.Project{{ID: "p1", Name: "Inbox", InboxProject: true}}, false, `section "Errands" not found`, 0},
+		{"created in the Inbox", []todoist.Project{{ID: "p1", Name: "Inbox", InboxProject: true}}, true, "", 2},
+		{"no cached Inbox", nil, true, `line 2: cannot create section "Errands"`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &cache.Cache{Projects: tt.projects}
+			plan, err := planImport(store, tasks, tt.create)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("planImport() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plan.commands) != tt.wantCmds {
+				t.Fatalf("planImport() made %d commands, want %d", len(plan.commands), tt.wantCmds)
+			}
+			section, task := plan.commands[0], plan.commands[1]
+			if section.Args["project_id"] != "p1" || task.Args["section_id"] != section.TempID {
+				t.Errorf("section args %v and task args %v, want the section in p1 holding the task", section.Args, task.Args)
+			}
+		})
+	}
+}
@@ -0,0 +1,470 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// Import file formats
+const (
+	ImportCSV         = "csv"
+	ImportTodoTxt     = "todotxt"
+	ImportTaskwarrior = "taskwarrior"
+)
+
+// ImportFormats lists the formats ParseImport reads
+var ImportFormats = []string{ImportCSV, ImportTodoTxt, ImportTaskwarrior}
+
+// ImportTask is a task read from an import file, with its project, section and parent still to be resolved
+type ImportTask struct {
+	todoist.CreateTaskOptions
+	// Line is where the task was read, or its position in a Taskwarrior export
+	Line int `json:"line"`
+	// Key identifies the task within the file, Parent is the Key of its parent task
+	Key    string `json:"key,omitempty"`
+	Parent string `json:"parent,omitempty"`
+	// Project is the path of project names from the outermost one, empty for the default project
+	Project []string `json:"project,omitempty"`
+	Section string   `json:"section,omitempty"`
+	// Depth is how many parents the task has within the file
+	Depth int `json:"-"`
+}
+
+// ImportFormat guesses the format of an import file from its extension
+func ImportFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ImportCSV, nil
+	case ".txt":
+		return ImportTodoTxt, nil
+	case ".json":
+		return ImportTaskwarrior, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %q, pass --format %s", path, strings.Join(ImportFormats, "|"))
+}
+
+// ParseImport reads the tasks of an import file, ordering parents before their subtasks.
+// Completed and deleted tasks are skipped and counted.
+func ParseImport(r io.Reader, format string) ([]ImportTask, int, error) {
+	var tasks []ImportTask
+	var skipped int
+	var err error
+	switch format {
+	case ImportCSV:
+		tasks, skipped, err = parseCSV(r)
+	case ImportTodoTxt:
+		tasks, skipped, err = parseTodoTxt(r)
+	case ImportTaskwarrior:
+		tasks, skipped, err = parseTaskwarrior(r)
+	default:
+		return nil, 0, fmt.Errorf("unknown import format %q, use one of: %s", format, strings.Join(ImportFormats, ", "))
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	tasks, err = linkTasks(tasks)
+	return tasks, skipped, err
+}
+
+// csvColumns maps the accepted CSV headers to the field they fill, including those of Todoist's own CSV template
+var csvColumns = map[string]string{
+	"content":       "content",
+	"task":          "content",
+	"title":         "content",
+	"name":          "content",
+	"description":   "description",
+	"notes":         "description",
+	"project":       "project",
+	"section":       "section",
+	"labels":        "labels",
+	"label":         "labels",
+	"tags":          "labels",
+	"priority":      "priority",
+	"due":           "due",
+	"due_date":      "due",
+	"date":          "due",
+	"due_lang":      "due_lang",
+	"date_lang":     "due_lang",
+	"deadline":      "deadline",
+	"duration":      "duration",
+	"duration_unit": "duration_unit",
+	"id":            "id",
+	"parent":        "parent",
+	"parent_id":     "parent",
+	"indent":        "indent",
+	"type":          "type",
+	"completed":     "completed",
+	"done":          "completed",
+}
+
+// parseCSV reads a CSV file with a header row. Subtasks are given either by an id and parent column,
+// or by an indent column as in Todoist's template, where a section row puts the tasks below it in that section.
+func parseCSV(r io.Reader) ([]ImportTask, int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := csvColumns[name]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["content"]; !ok {
+		return nil, 0, fmt.Errorf("the CSV header has no content column")
+	}
+
+	var tasks []ImportTask
+	var skipped int
+	var section string
+	// parents holds the key of the last task seen at each indent level
+	var parents []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		line, _ := reader.FieldPos(0)
+		get := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		switch strings.ToLower(get("type")) {
+		case "", "task":
+		case "section":
+			section = get("content")
+			parents = nil
+			continue
+		default:
+			// Notes and other rows of Todoist's template have no task to create
+			continue
+		}
+		if get("content") == "" {
+			continue
+		}
+		if done, _ := strconv.ParseBool(get("completed")); done {
+			skipped++
+			continue
+		}
+
+		t := ImportTask{Line: line, Key: get("id"), Parent: get("parent"), Section: section}
+		t.Content = get("content")
+		t.Description = get("description")
+		t.DueLang = get("due_lang")
+		if project := get("project"); project != "" {
+			t.Project = []string{project}
+		}
+		if s := get("section"); s != "" {
+			t.Section = s
+		}
+		for _, label := range strings.Split(get("labels"), ",") {
+			if label = strings.TrimPrefix(strings.TrimSpace(label), "@"); label != "" {
+				t.Labels = append(t.Labels, label)
+			}
+		}
+		if priority := get("priority"); priority != "" {
+			if t.Priority, err = ParsePriority(priority); err != nil {
+				return nil, 0, lineError(line, err)
+			}
+		}
+		setDue(&t.CreateTaskOptions, get("due"))
+		if deadline := get("deadline"); deadline != "" {
+			if t.DeadlineDate, err = ParseDate(deadline); err != nil {
+				return nil, 0, lineError(line, err)
+			}
+		}
+		if t.Duration, t.DurationUnit, err = parseImportDuration(get("duration"), get("duration_unit")); err != nil {
+			return nil, 0, lineError(line, err)
+		}
+
+		if indent := get("indent"); indent != "" {
+			level, err := strconv.Atoi(indent)
+			if err != nil || level < 1 || level > len(parents)+1 {
+				return nil, 0, lineError(line, fmt.Errorf("invalid indent %q", indent))
+			}
+			if t.Key == "" {
+				t.Key = "line " + strconv.Itoa(line)
+			}
+			if level > 1 {
+				t.Parent = parents[level-2]
+			}
+			parents = append(parents[:level-1], t.Key)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, skipped, nil
+}
+
+// parseImportDuration reads a duration like 1h30m, or a plain amount when the file has a separate unit
+func parseImportDuration(duration, unit string) (int, string, error) {
+	if duration == "" {
+		return 0, "", nil
+	}
+	amount, err := strconv.Atoi(duration)
+	if err != nil {
+		return ParseDuration(duration)
+	}
+	switch strings.TrimSuffix(strings.ToLower(unit), "s") {
+	case "", "minute":
+		return amount, "minute", nil
+	case "day":
+		return amount, "day", nil
+	}
+	return 0, "", fmt.Errorf("invalid duration unit %q, use minute or day", unit)
+}
+
+// parseTodoTxt reads a todo.txt file. +project sets the project and @context adds a label,
+// due:, id: and p: (or parent:) tags set the due date and link subtasks to their parent.
+func parseTodoTxt(r io.Reader) ([]ImportTask, int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+
+	var tasks []ImportTask
+	var skipped int
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if strings.HasPrefix(text, "x ") {
+			skipped++
+			continue
+		}
+
+		t := ImportTask{Line: line}
+		if len(text) > 3 && text[0] == '(' && text[1] >= 'A' && text[1] <= 'Z' && text[2] == ')' && text[3] == ' ' {
+			// (A) to (C) are p1 to p3, anything lower is p4
+			t.Priority = 4 - min(int(text[1]-'A'), 3)
+			text = strings.TrimSpace(text[4:])
+		}
+		if date, rest, _ := strings.Cut(text, " "); isDate(date) {
+			// The creation date has no counterpart in Todoist
+			text = rest
+		}
+
+		var words []string
+		for _, word := range strings.Fields(text) {
+			if len(word) > 1 && word[0] == '+' && t.Project == nil {
+				t.Project = []string{word[1:]}
+				continue
+			}
+			if len(word) > 1 && word[0] == '@' {
+				t.Labels = append(t.Labels, word[1:])
+				continue
+			}
+			key, value, _ := strings.Cut(word, ":")
+			switch {
+			case value == "":
+				words = append(words, word)
+			case key == "due":
+				setDue(&t.CreateTaskOptions, value)
+			case key == "id":
+				t.Key = value
+			case key == "p" || key == "parent":
+				t.Parent = value
+			default:
+				words = append(words, word)
+			}
+		}
+		t.Content = strings.Join(words, " ")
+		if t.Content == "" {
+			return nil, 0, lineError(line, fmt.Errorf("task has no text"))
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, skipped, scanner.Err()
+}
+
+// taskwarriorTask holds the fields of a 'task export' entry that have a counterpart in Todoist
+type taskwarriorTask struct {
+	UUID        string   `json:"uuid"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Project     string   `json:"project"`
+	Tags        []string `json:"tags"`
+	Priority    string   `json:"priority"`
+	Due         string   `json:"due"`
+	Annotations []struct {
+		Description string `json:"description"`
+	} `json:"annotations"`
+}
+
+// taskwarriorPriorities maps Taskwarrior's priorities to the API's, where 4 is urgent
+var taskwarriorPriorities = map[string]int{"H": 4, "M": 3, "L": 2}
+
+// parseTaskwarrior reads the output of 'task export', either a JSON array or one task per line.
+// Dotted projects like Home.Garden become nested projects, annotations become the description.
+func parseTaskwarrior(r io.Reader) ([]ImportTask, int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	data = bytes.TrimSpace(data)
+
+	var entries []taskwarriorTask
+	if bytes.HasPrefix(data, []byte("[")) {
+		err = json.Unmarshal(data, &entries)
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for decoder.More() {
+			var entry taskwarriorTask
+			if err = decoder.Decode(&entry); err != nil {
+				break
+			}
+			entries = append(entries, entry)
+		}
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid Taskwarrior export: %w", err)
+	}
+
+	var tasks []ImportTask
+	var skipped int
+	for i, entry := range entries {
+		switch entry.Status {
+		case "completed", "deleted":
+			skipped++
+			continue
+		case "recurring":
+			// Templates of recurring tasks, whose pending instances are exported as well
+			continue
+		}
+
+		t := ImportTask{Line: i + 1, Key: entry.UUID}
+		t.Content = entry.Description
+		t.Labels = entry.Tags
+		t.Priority = taskwarriorPriorities[entry.Priority]
+		if entry.Project != "" {
+			t.Project = strings.Split(entry.Project, ".")
+		}
+		var notes []string
+		for _, a := range entry.Annotations {
+			notes = append(notes, a.Description)
+		}
+		t.Description = strings.Join(notes, "\n")
+		if entry.Due != "" {
+			due, err := time.Parse("20060102T150405Z", entry.Due)
+			if err != nil {
+				return nil, 0, fmt.Errorf("task %d: invalid due date %q", i+1, entry.Due)
+			}
+			if local := due.Local(); local.Hour() == 0 && local.Minute() == 0 && local.Second() == 0 {
+				// Taskwarrior stores due dates without a time as local midnight
+				t.DueDate = local.Format("2006-01-02")
+			} else {
+				t.DueDatetime = due.Format(time.RFC3339)
+			}
+		}
+		if t.Content == "" {
+			return nil, 0, fmt.Errorf("task %d has no description", i+1)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, skipped, nil
+}
+
+// linkTasks checks that every parent exists and orders parents before their subtasks.
+// Subtasks always go to the project and section of their parent.
+func linkTasks(tasks []ImportTask) ([]ImportTask, error) {
+	byKey := make(map[string]int)
+	for i, t := range tasks {
+		if t.Key == "" {
+			continue
+		}
+		if j, ok := byKey[t.Key]; ok {
+			return nil, lineError(t.Line, fmt.Errorf("id %q is already used on line %d", t.Key, tasks[j].Line))
+		}
+		byKey[t.Key] = i
+	}
+
+	children := make(map[int][]int)
+	var roots []int
+	for i, t := range tasks {
+		if t.Parent == "" {
+			roots = append(roots, i)
+			continue
+		}
+		parent, ok := byKey[t.Parent]
+		if !ok {
+			return nil, lineError(t.Line, fmt.Errorf("parent %q not found", t.Parent))
+		}
+		children[parent] = append(children[parent], i)
+	}
+
+	ordered := make([]ImportTask, 0, len(tasks))
+	visited := make([]bool, len(tasks))
+	var visit func(i, depth int, parent *ImportTask)
+	visit = func(i, depth int, parent *ImportTask) {
+		t := tasks[i]
+		t.Depth = depth
+		if parent != nil {
+			t.Project, t.Section = parent.Project, parent.Section
+		}
+		visited[i] = true
+		ordered = append(ordered, t)
+		for _, child := range children[i] {
+			visit(child, depth+1, &t)
+		}
+	}
+	for _, i := range roots {
+		visit(i, 0, nil)
+	}
+	for i, seen := range visited {
+		if !seen {
+			return nil, lineError(tasks[i].Line, fmt.Errorf("task is its own ancestor"))
+		}
+	}
+	return ordered, nil
+}
+
+// setDue sets a due date, a due time or a natural language due string, depending on what s looks like
+func setDue(options *todoist.CreateTaskOptions, s string) {
+	if s == "" {
+		return
+	}
+	if isDate(s) {
+		options.DueDate = s
+	} else if t, err := time.Parse(time.RFC3339, s); err == nil {
+		options.DueDatetime = t.UTC().Format(time.RFC3339)
+	} else {
+		options.DueString = s
+	}
+}
+
+func isDate(s string) bool {
+	_, err := time.Parse("2006-01-02", s)
+	return err == nil
+}
+
+func lineError(line int, err error) error {
+	return fmt.Errorf("line %d: %w", line, err)
+}
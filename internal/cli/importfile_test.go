@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+func TestParseImport(t *testing.T) {
+	// Taskwarrior keeps dates without a time as local midnight
+	midnight := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local).UTC().Format("20060102T150405Z")
+
+	tests := []struct {
+		name        string
+		format      string
+		input       string
+		want        []ImportTask
+		wantSkipped int
+	}{
+		{
+			name:   "csv fields",
+			format: ImportCSV,
+			input: "\ufeffTask,Notes,Project,Labels,Priority,Due,Deadline,Duration\n" +
+				"Buy milk,two litres,Home,\"@shop, errand\",p1,2026-10-20,2026-10-22,90\n" +
+				"Call mom,,,,,tomorrow 5pm,,1h30m\n" +
+				"Fly,,,,,2026-10-20T08:00:00+02:00,,2d\n",
+			want: []ImportTask{
+				{
+					CreateTaskOptions: todoist.CreateTaskOptions{
+						Content: "Buy milk", Description: "two litres", Labels: []string{"shop", "errand"}, Priority: 4,
+						DueDate: "2026-10-20", DeadlineDate: "2026-10-22", Duration: 90, DurationUnit: "minute",
+					},
+					Line: 2, Project: []string{"Home"},
+				},
+				{
+					CreateTaskOptions: todoist.CreateTaskOptions{Content: "Call mom", DueString: "tomorrow 5pm", Duration: 90, DurationUnit: "minute"},
+					Line:              3,
+				},
+				{
+					CreateTaskOptions: todoist.CreateTaskOptions{Content: "Fly", DueDatetime: "2026-10-20T06:00:00Z", Duration: 2, DurationUnit: "day"},
+					Line:              4,
+				},
+			},
+		},
+		{
+			name:   "csv todoist template with sections and indents",
+			format: ImportCSV,
+			input: "TYPE,CONTENT,PRIORITY,INDENT\n" +
+				"section,Errands,,\n" +
+				"task,Buy milk,1,1\n" +
+				"task,Oat milk,1,2\n" +
+				"note,remember the receipt,,\n" +
+				"task,Post office,4,1\n",
+			want: []ImportTask{
+				{CreateTaskOptions: todoist.CreateTaskOptions{Content: "Buy milk", Priority: 4}, Line: 3, Key: "line 3", Section: "Errands"},
+				{CreateTaskOptions: todoist.CreateTaskOptions{Content: "Oat milk", Priority: 4}, Line: 4, Key: "line 4", Parent: "line 3", Section: "Errands", Depth: 1},
+				{CreateTaskOptions: todoist.CreateTaskOptions{Content: "Post office", Priority: 1}, Line: 6, Key: "line 6", Section: "Errands"},
+			},
+		},
+		{
+			name:   "csv parent ids listed after their subtasks",
+			format: ImportCSV,
+			input: "content,id,parent,project,section,completed\n" +
+				"Draft,2,1,,,\n" +
+				"Report,1,,Work,Q4,\n" +
+				"Old,3,,,,true\n",
+			want: []ImportTask{
+				{CreateTaskOptions: todoist.CreateTaskOptions{Content: "Report"}, Line: 3, Key: "1", Project: []string{"Work"}, Section: "Q4"},
+				{CreateTaskOptions: todoist.CreateTaskOptions{Content: "Draft"}, Line: 2, Key: "2", Parent: "1", Project: []string{"Work"}, Section: "Q4", Depth: 1},
+			},
+			wantSkipped: 1,
+		},
+		{
+			name:   "csv header only",
+			format: ImportCSV,
+			input:  "content\n",
+			want:   []ImportTask{},
+		},
+		{
+			name:   "todo.txt",
+			format: ImportTodoTxt,
+			input: "(A) 2026-10-01 Call mom +Family @phone due:2026-10-20 id:1\n" +
+				"x 2026-10-02 Done already\n" +
+				"\n" +
+				"Buy flowers p:1 @shop +Ignored\n" +
+				"(D) Someday url:http://example.com\n",
+			want: []ImportTask{
+				{
+					CreateTaskOptions: todoist.CreateTaskOptions{Content: "Call mom", Priority: 4, Labels: []string{"phone"}, DueDate: "2026-10-20"},
+					Line:              1, Key: "1", Project: []string{"Family"},
+				},
+				{
+					CreateTaskOptions: todoist.CreateTaskOptions{Content: "Buy flowers", Labels: []string{"shop"}},
+					Line:              4, Parent: "1", Project: []string{"Family"}, Depth: 1,
+				},
+				{CreateTaskOptions: todoist.CreateTaskOptions{Content: "Someday url:http://example.com", Priority: 1}, Line: 5},
+			},
+			wantSkipped: 1,
+		},
+		{
+			name:   "taskwarrior array",
+			format: ImportTaskwarrior,
+			input: `[{"uuid":"a","description":"Dig","status":"pending","project":"Home.Garden","tags":["out"],"priority":"H","due":"` + midnight + `","annotations":[{"description":"bring gloves"},{"description":"and seeds"}]},
+				{"uuid":"b","description":"Old","status":"completed"},
+				{"uuid":"c","description":"Template","status":"recurring"},
+				{"uuid":"d","description":"Timed","status":"pending","priority":"L","due":"20261020T151700Z"}]`,
+			want: []ImportTask{
+				{
+					CreateTaskOptions: todoist.CreateTaskOptions{Content: "Dig", Description: "bring gloves\nand seeds", Labels: []string{"out"}, Priority: 4, DueDate: "2026-10-20"},
+					Line:              1, Key: "a", Project: []string{"Home", "Garden"},
+				},
+				{CreateTaskOptions: todoist.CreateTaskOptions{Content: "Timed", Priority: 2, DueDatetime: "2026-10-20T15:17:00Z"}, Line: 4, Key: "d"},
+			},
+			wantSkipped: 1,
+		},
+		{
+			name:   "taskwarrior lines",
+			format: ImportTaskwarrior,
+			input: `{"uuid":"a","description":"One","status":"pending"}
+{"uuid":"b","description":"Two","status":"deleted"}
+{"uuid":"c","description":"Three","status":"waiting"}`,
+			want: []ImportTask{
+				{CreateTaskOptions: todoist.CreateTaskOptions{Content: "One"}, Line: 1, Key: "a"},
+				{CreateTaskOptions: todoist.CreateTaskOptions{Content: "Three"}, Line: 3, Key: "c"},
+			},
+			wantSkipped: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, skipped, err := ParseImport(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseImport() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+			if skipped != tt.wantSkipped {
+				t.Errorf("ParseImport() skipped %d, want %d", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestParseImportErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		want   string
+	}{
+		{"unknown format", "xml", "", "unknown import format"},
+		{"csv without content column", ImportCSV, "notes,project\nx,y\n", "no content column"},
+		{"csv indent skipping a level", ImportCSV, "content,indent\na,1\nb,3\n", `line 3: invalid indent "3"`},
+		{"csv indent below one", ImportCSV, "content,indent\na,0\n", `line 2: invalid indent "0"`},
+		{"csv invalid priority", ImportCSV, "content,priority\na,p7\n", "line 2: invalid priority"},
+		{"csv invalid deadline", ImportCSV, "content,deadline\na,friday\n", "line 2: invalid date"},
+		{"csv invalid duration unit", ImportCSV, "content,duration,duration_unit\na,3,week\n", "line 2: invalid duration unit"},
+		{"csv missing parent", ImportCSV, "content,id,parent\na,1,\nb,2,9\n", `line 3: parent "9" not found`},
+		{"todo.txt without text", ImportTodoTxt, "(A) @phone +Home\n", "line 1: task has no text"},
+		{"taskwarrior invalid json", ImportTaskwarrior, "[{]", "invalid Taskwarrior export"},
+		{"taskwarrior without description", ImportTaskwarrior, `[{"uuid":"a","status":"pending"}]`, "task 1 has no description"},
+		{"taskwarrior invalid due", ImportTaskwarrior, `[{"uuid":"a","description":"x","status":"pending","due":"tomorrow"}]`, `task 1: invalid due date "tomorrow"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseImport(strings.NewReader(tt.input), tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseImport() error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkTasks(t *testing.T) {
+	task := func(line int, key, parent string) ImportTask {
+		return ImportTask{Line: line, Key: key, Parent: parent}
+	}
+
+	tests := []struct {
+		name    string
+		tasks   []ImportTask
+		want    []int
+		wantErr string
+	}{
+		{
+			name:  "no subtasks keep their order",
+			tasks: []ImportTask{task(1, "", ""), task(2, "b", ""), task(3, "", "")},
+			want:  []int{1, 2, 3},
+		},
+		{
+			name:  "subtasks follow their parent in file order",
+			tasks: []ImportTask{task(1, "a", ""), task(2, "b", ""), task(3, "c", "a"), task(4, "d", "b"), task(5, "e", "a")},
+			want:  []int{1, 3, 5, 2, 4},
+		},
+		{
+			name:  "parents listed after their subtasks",
+			tasks: []ImportTask{task(1, "c", "b"), task(2, "b", "a"), task(3, "a", "")},
+			want:  []int{3, 2, 1},
+		},
+		{
+			name:    "missing parent",
+			tasks:   []ImportTask{task(1, "a", ""), task(2, "b", "x")},
+			wantErr: `line 2: parent "x" not found`,
+		},
+		{
+			name:    "duplicate key",
+			tasks:   []ImportTask{task(1, "a", ""), task(2, "a", "")},
+			wantErr: `line 2: id "a" is already used on line 1`,
+		},
+		{
+			name:    "own parent",
+			tasks:   []ImportTask{task(1, "a", ""), task(2, "b", "b")},
+			wantErr: "line 2: task is its own ancestor",
+		},
+		{
+			name:    "cycle",
+			tasks:   []ImportTask{task(1, "a", "c"), task(2, "b", "a"), task(3, "c", "b"), task(4, "d", "")},
+			wantErr: "line 1: task is its own ancestor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := linkTasks(tt.tasks)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("linkTasks() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var lines []int
+			for _, t := range got {
+				lines = append(lines, t.Line)
+			}
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Errorf("linkTasks() lines = %v, want %v", lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkTasksInheritsLocation(t *testing.T) {
+	tasks := []ImportTask{
+		{Line: 1, Key: "a", Project: []string{"Work"}, Section: "Q4"},
+		{Line: 2, Key: "b", Parent: "a", Project: []string{"Home"}},
+		{Line: 3, Parent: "b", Section: "Other"},
+	}
+	got, err := linkTasks(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, task := range got {
+		if !reflect.DeepEqual(task.Project, []string{"Work"}) || task.Section != "Q4" || task.Depth != i {
+			t.Errorf("task on line %d has project %v, section %q and depth %d, want [Work], Q4 and %d",
+				task.Line, task.Project, task.Section, task.Depth, i)
+		}
+	}
+}
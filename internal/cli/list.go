@@ -2,39 +2,122 @@ package cli
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/query"
 	"github.com/mdjarv/todoist-cli/internal/todoist"
 )
 
+// ListOptions selects, orders and limits the listed tasks
+type ListOptions struct {
+	Cache  cache.Options
+	Filter query.Filter
+	// Project and Section are names or IDs, resolved into Filter
+	Project string
+	Section string
+	// AssignedTo is a user ID, or "me" for the logged in user
+	AssignedTo string
+	Sort       []query.SortKey
+	// Limit caps the number of tasks printed, 0 for no limit
+	Limit int
+	// Tree nests subtasks under their parents
+	Tree bool
+}
+
 // List displays a simple CLI list of tasks
-func List(ctx context.Context, client todoist.Client, jsonOut bool) error {
-	// Fetch tasks from Todoist API
-	tasksResp, err := client.ListTasks(ctx, nil)
+func List(ctx context.Context, client todoist.Client, opts ListOptions, out output.Options) error {
+	// Fetch tasks from the local cache, syncing with Todoist if it is stale
+	store, err := cache.Fetch(ctx, client, opts.Cache)
 	if err != nil {
 		return fmt.Errorf("failed to fetch tasks: %w", err)
 	}
 
-	if len(tasksResp.Results) == 0 {
+	filter := opts.Filter
+	if err := resolveFilter(ctx, client, store, opts, &filter); err != nil {
+		return err
+	}
+
+	tasks, projectNames := store.Snapshot()
+	tasks = query.Apply(tasks, filter, time.Now())
+	query.Sort(tasks, opts.Sort, projectNames)
+	if opts.Tree {
+		var completed []todoist.Task
+		if !out.Structured() && !opts.Cache.Offline {
+			// Only counted on their parents, a failure leaves the counts at the open subtasks
+			if completed, err = store.CompletedSubtasks(ctx, client); err != nil {
+				fmt.Fprintln(os.Stderr, "failed to fetch completed subtasks:", err)
+			}
+		}
+		tasks = treeOrder(tasks, completed, out)
+	}
+	if opts.Limit > 0 && len(tasks) > opts.Limit {
+		tasks = tasks[:opts.Limit]
+	}
+
+	if len(tasks) == 0 && !out.Structured() {
 		fmt.Println("No tasks found.")
 		return nil
 	}
 
-	// Display tasks in JSON if requested
-	if jsonOut {
-		importedJson, err := json.MarshalIndent(tasksResp.Results, "", "  ")
+	return printTasks(tasks, store.Names(), out)
+}
+
+// resolveFilter fills in the filter's project, section and assignee IDs from names
+func resolveFilter(ctx context.Context, client todoist.Client, store *cache.Cache, opts ListOptions, filter *query.Filter) error {
+	if opts.Project != "" || opts.Section != "" {
+		err := findWithRefresh(ctx, client, store, opts.Cache.Offline, func() error {
+			if opts.Project != "" {
+				project, err := store.FindProject(opts.Project)
+				if err != nil {
+					return err
+				}
+				filter.ProjectID = project.ID
+			}
+			if opts.Section != "" {
+				section, err := store.FindSection(filter.ProjectID, opts.Section)
+				if err != nil {
+					return err
+				}
+				filter.SectionID = section.ID
+			}
+			return nil
+		})
 		if err != nil {
-			return fmt.Errorf("failed to marshal tasks to JSON: %w", err)
+			return err
 		}
-		fmt.Println(string(importedJson))
-		return nil
 	}
 
-	// Display header
-	fmt.Println("ID\tContent\tProject")
-	for _, task := range tasksResp.Results {
-		fmt.Printf("%s\t%s\t%s\n", task.ID, task.Content, task.ProjectID)
+	switch opts.AssignedTo {
+	case "":
+	case "me":
+		if opts.Cache.Offline {
+			return fmt.Errorf("--assigned-to me needs to look up the user, pass a user ID when offline")
+		}
+		user, err := client.GetUser(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to look up user: %w", err)
+		}
+		filter.AssignedTo = user.ID
+	default:
+		filter.AssignedTo = opts.AssignedTo
 	}
 	return nil
 }
+
+// treeOrder puts subtasks below their parents. Tables also get the tree lines and subtask counts
+// in the content, counting the completed subtasks without listing them. Structured formats keep the tasks unchanged.
+func treeOrder(tasks, completed []todoist.Task, out output.Options) []todoist.Task {
+	rows := query.Flatten(query.BuildTree(append(tasks, completed...)), func(t todoist.Task) bool { return t.Checked })
+	ordered := make([]todoist.Task, len(rows))
+	for i, row := range rows {
+		ordered[i] = row.Task
+		if !out.Structured() {
+			ordered[i].Content = row.Content()
+		}
+	}
+	return ordered
+}
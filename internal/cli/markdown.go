@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles of rendered Markdown, only applied when output is colored
+var (
+	markdownBold    = lipgloss.NewStyle().Bold(true)
+	markdownItalic  = lipgloss.NewStyle().Italic(true)
+	markdownCode    = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	markdownLink    = lipgloss.NewStyle().Foreground(lipgloss.Color("12")).Underline(true)
+	markdownQuote   = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	markdownHeading = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+	bulletPattern   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	checkboxPattern = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	rulePattern     = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+
+	// Inline patterns are applied in order, code first so its contents are left alone
+	codePattern   = regexp.MustCompile("`([^`]+)`")
+	linkPattern   = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldPattern   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	italicPattern = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+)
+
+// renderMarkdown formats the Markdown Todoist uses in descriptions and comments for the terminal.
+// Without styling the markup is dropped, leaving readable plain text.
+func renderMarkdown(text string, styled bool) string {
+	style := func(s lipgloss.Style, text string) string {
+		if !styled {
+			return text
+		}
+		return s.Render(text)
+	}
+
+	var lines []string
+	inFence := false
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			lines = append(lines, "    "+style(markdownCode, line))
+			continue
+		}
+
+		switch {
+		case headingPattern.MatchString(line):
+			heading := headingPattern.FindStringSubmatch(line)[1]
+			lines = append(lines, style(markdownHeading, renderInline(heading, false, style)))
+		case rulePattern.MatchString(line):
+			lines = append(lines, strings.Repeat("─", 20))
+		case bulletPattern.MatchString(line):
+			m := bulletPattern.FindStringSubmatch(line)
+			marker, item := "•", m[2]
+			if c := checkboxPattern.FindStringSubmatch(item); c != nil {
+				marker, item = "☐", c[2]
+				if c[1] != " " {
+					marker = "☑"
+				}
+			}
+			lines = append(lines, m[1]+marker+" "+renderInline(item, styled, style))
+		case strings.HasPrefix(line, ">"):
+			quote := strings.TrimSpace(strings.TrimPrefix(line, ">"))
+			lines = append(lines, style(markdownQuote, "│ "+renderInline(quote, false, style)))
+		default:
+			lines = append(lines, renderInline(line, styled, style))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderInline handles code spans, links, bold and italic text within a line
+func renderInline(line string, styled bool, style func(lipgloss.Style, string) string) string {
+	if !styled {
+		style = func(_ lipgloss.Style, text string) string { return text }
+	}
+
+	// Code spans are cut out first so markup inside them is shown literally
+	var spans []string
+	line = codePattern.ReplaceAllStringFunc(line, func(m string) string {
+		spans = append(spans, style(markdownCode, codePattern.FindStringSubmatch(m)[1]))
+		return "\x00"
+	})
+
+	line = linkPattern.ReplaceAllStringFunc(line, func(m string) string {
+		parts := linkPattern.FindStringSubmatch(m)
+		if parts[1] == parts[2] {
+			return style(markdownLink, parts[2])
+		}
+		return parts[1] + " (" + style(markdownLink, parts[2]) + ")"
+	})
+	line = boldPattern.ReplaceAllStringFunc(line, func(m string) string {
+		parts := boldPattern.FindStringSubmatch(m)
+		return style(markdownBold, parts[1]+parts[2])
+	})
+	line = italicPattern.ReplaceAllStringFunc(line, func(m string) string {
+		return style(markdownItalic, italicPattern.FindStringSubmatch(m)[1])
+	})
+
+	for _, span := range spans {
+		line = strings.Replace(line, "\x00", span, 1)
+	}
+	return line
+}
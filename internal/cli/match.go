@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// Match quality, lower is better
+const (
+	matchExact = iota
+	matchPrefix
+	matchSubstring
+	matchWords
+	matchSubsequence
+	noMatch
+)
+
+// FuzzyMatch returns the tasks whose content best matches query, ignoring case.
+// Only the best quality of match is returned, so an exact match hides looser ones.
+func FuzzyMatch(query string, tasks []todoist.Task) []todoist.Task {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+
+	best := noMatch
+	var matches []todoist.Task
+	for _, task := range tasks {
+		score := matchScore(query, strings.ToLower(task.Content))
+		switch {
+		case score < best:
+			best = score
+			matches = []todoist.Task{task}
+		case score == best && score != noMatch:
+			matches = append(matches, task)
+		}
+	}
+	return matches
+}
+
+func matchScore(query, content string) int {
+	switch {
+	case content == query:
+		return matchExact
+	case strings.HasPrefix(content, query):
+		return matchPrefix
+	case strings.Contains(content, query):
+		return matchSubstring
+	case containsWords(content, strings.Fields(query)):
+		return matchWords
+	case isSubsequence(query, content):
+		return matchSubsequence
+	default:
+		return noMatch
+	}
+}
+
+// containsWords reports whether every word appears in s, in any order
+func containsWords(s string, words []string) bool {
+	if len(words) < 2 {
+		return false
+	}
+	for _, w := range words {
+		if !strings.Contains(s, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSubsequence reports whether the letters and digits of query appear in s in order
+func isSubsequence(query, s string) bool {
+	runes := []rune(s)
+	i := 0
+	for _, q := range query {
+		if !unicode.IsLetter(q) && !unicode.IsDigit(q) {
+			continue
+		}
+		for i < len(runes) && runes[i] != q {
+			i++
+		}
+		if i == len(runes) {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// looksLikeID reports whether s could be a task ID rather than a search query
+func looksLikeID(s string) bool {
+	if len(s) < 6 || strings.ContainsFunc(s, unicode.IsSpace) {
+		return false
+	}
+	return strings.ContainsFunc(s, unicode.IsDigit)
+}
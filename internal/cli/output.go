@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// priorityColors highlight p1 to p3 like the Todoist apps do
+var priorityColors = map[int]string{4: "9", 3: "208", 2: "12"}
+
+const overdueColor = "9"
+
+// taskTable describes the task columns, showing project and section names from names
+func taskTable(names cache.Names, dateFormat string) output.Table[todoist.Task] {
+	if dateFormat == "" {
+		dateFormat = "2006-01-02"
+	}
+	now := time.Now()
+
+	return output.Table[todoist.Task]{
+		Default: []string{"id", "priority", "content", "project", "due", "labels"},
+		Columns: []output.Column[todoist.Task]{
+			{Name: "id", Header: "ID", Value: func(t todoist.Task) string { return t.ID }},
+			{
+				Name: "priority", Header: "Pri",
+				Value: func(t todoist.Task) string { return FormatPriority(t.Priority) },
+				Color: func(t todoist.Task) string { return priorityColors[t.Priority] },
+			},
+			{Name: "content", Header: "Content", Value: func(t todoist.Task) string { return t.Content }, Shrink: true},
+			{Name: "description", Header: "Description", Value: func(t todoist.Task) string { return t.Description }, Shrink: true},
+			{Name: "project", Header: "Project", Value: func(t todoist.Task) string { return nameOr(names.Projects, t.ProjectID) }, Shrink: true},
+			{Name: "section", Header: "Section", Value: func(t todoist.Task) string { return nameOr(names.Sections, t.SectionID) }, Shrink: true},
+			{Name: "parent", Header: "Parent", Value: func(t todoist.Task) string { return t.ParentID }},
+			{
+				Name: "due", Header: "Due",
+				Value: func(t todoist.Task) string { return formatDue(t, dateFormat) },
+				Color: func(t todoist.Task) string {
+					if (query.Filter{Due: "overdue"}).Match(t, now) {
+						return overdueColor
+					}
+					return ""
+				},
+			},
+			{Name: "time", Header: "Time", Value: formatDueTime},
+			{Name: "deadline", Header: "Deadline", Value: func(t todoist.Task) string { return formatDateField(t.Deadline, dateFormat) }},
+			{Name: "duration", Header: "Duration", Value: formatTaskDuration},
+			{Name: "labels", Header: "Labels", Value: func(t todoist.Task) string { return strings.Join(t.Labels, ",") }, Shrink: true},
+			{Name: "assignee", Header: "Assignee", Value: func(t todoist.Task) string { return t.ResponsibleUID }},
+			{Name: "created", Header: "Created", Value: func(t todoist.Task) string { return t.AddedAt }},
+			{Name: "completed", Header: "Completed", Value: func(t todoist.Task) string { return t.CompletedAt }},
+			{Name: "checked", Header: "Done", Value: func(t todoist.Task) string { return strconv.FormatBool(t.Checked) }},
+		},
+	}
+}
+
+// resultTable describes the columns of tasks changed by a command: the task columns and whether the change is queued
+func resultTable(names cache.Names, dateFormat string) output.Table[TaskResult] {
+	table := output.Table[TaskResult]{Default: []string{"id", "content", "project", "queued"}}
+	for _, c := range taskTable(names, dateFormat).Columns {
+		column := output.Column[TaskResult]{
+			Name: c.Name, Header: c.Header, Shrink: c.Shrink,
+			Value: func(r TaskResult) string { return c.Value(r.Task) },
+		}
+		if c.Color != nil {
+			column.Color = func(r TaskResult) string { return c.Color(r.Task) }
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	table.Columns = append(table.Columns, output.Column[TaskResult]{
+		Name: "queued", Header: "Queued", Value: func(r TaskResult) string { return strconv.FormatBool(r.Queued) },
+	})
+	return table
+}
+
+// nameOr returns the name of id, or id itself when the name is not known
+func nameOr(names map[string]string, id string) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	return id
+}
+
+// formatDue shows the due date in layout, with the time of day if it has one
+func formatDue(t todoist.Task, layout string) string {
+	due, ok := query.DueTime(t)
+	if !ok {
+		return formatDateField(t.Due, layout)
+	}
+	if date, _ := t.Due["date"].(string); len(date) > len("2006-01-02") {
+		return due.Local().Format(layout + " 15:04")
+	}
+	return due.Format(layout)
+}
+
+// formatDueTime shows the local time of day a task is due, empty for tasks due on a date only
+func formatDueTime(t todoist.Task) string {
+	due, ok := query.DueTime(t)
+	if date, _ := t.Due["date"].(string); !ok || len(date) <= len("2006-01-02") {
+		return ""
+	}
+	return due.Local().Format("15:04")
+}
+
+func formatDateField(field map[string]any, layout string) string {
+	date, _ := field["date"].(string)
+	if parsed, err := time.Parse("2006-01-02", date); err == nil {
+		return parsed.Format(layout)
+	}
+	return date
+}
+
+func formatTaskDuration(t todoist.Task) string {
+	amount, _ := t.Duration["amount"].(float64)
+	unit, _ := t.Duration["unit"].(string)
+	if amount <= 0 {
+		return ""
+	}
+	return FormatDuration(int(amount), unit)
+}
+
+// printTasks writes tasks in the chosen output format
+func printTasks(tasks []todoist.Task, names cache.Names, out output.Options) error {
+	return taskTable(names, out.DateFormat).Write(out, tasks)
+}
+
+// printTask writes a single task in the chosen output format
+func printTask(task todoist.Task, names cache.Names, out output.Options) error {
+	return taskTable(names, out.DateFormat).WriteOne(out, task)
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsePriority converts p1 (urgent) to p4 (normal) into the API's priority, where 4 is urgent and 1 is normal
+func ParsePriority(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(s), "p"))
+	if err != nil || n < 1 || n > 4 {
+		return 0, fmt.Errorf("invalid priority %q, use p1 (urgent) to p4 (normal)", s)
+	}
+	return 5 - n, nil
+}
+
+// FormatPriority converts the API's priority back to p1..p4
+func FormatPriority(priority int) string {
+	if priority < 1 || priority > 4 {
+		priority = 1
+	}
+	return fmt.Sprintf("p%d", 5-priority)
+}
+
+// ParseDuration converts a duration like 30m, 1h30m or 2d into an amount and unit, minutes or days
+func ParseDuration(s string) (int, string, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, "", fmt.Errorf("invalid duration %q", s)
+		}
+		return n, "day", nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 || d%time.Minute != 0 {
+		return 0, "", fmt.Errorf("invalid duration %q, use whole minutes like 30m or 1h30m, or days like 2d", s)
+	}
+	return int(d / time.Minute), "minute", nil
+}
+
+// ParseDate checks that s is a YYYY-MM-DD date
+func ParseDate(s string) (string, error) {
+	if _, err := time.Parse("2006-01-02", s); err != nil {
+		return "", fmt.Errorf("invalid date %q, use YYYY-MM-DD", s)
+	}
+	return s, nil
+}
+
+// ReadFileArg returns the contents of path, or of stdin when path is "-"
+func ReadFileArg(path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
+
+// FormatDuration converts an API duration back to the form ParseDuration accepts
+func FormatDuration(amount int, unit string) string {
+	if unit == "day" {
+		return fmt.Sprintf("%dd", amount)
+	}
+	hours, minutes := amount/60, amount%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
+
+// ParseWeekdays converts comma separated days of the week, names like sat or numbers from 1 for Monday to 7 for Sunday,
+// into numbers. "none" gives no days.
+func ParseWeekdays(s string) ([]int, error) {
+	days := []int{}
+	if strings.EqualFold(s, "none") {
+		return days, nil
+	}
+	for _, field := range strings.Split(s, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		day, err := strconv.Atoi(field)
+		if err != nil {
+			day = 0
+			for d := time.Sunday; d <= time.Saturday; d++ {
+				if name := strings.ToLower(d.String()); len(field) >= 3 && strings.HasPrefix(name, field) {
+					day = cmp.Or(int(d), 7)
+				}
+			}
+		}
+		if day < 1 || day > 7 {
+			return nil, fmt.Errorf("invalid day %q, use names like sat or numbers from 1 for Monday to 7 for Sunday", field)
+		}
+		if !slices.Contains(days, day) {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
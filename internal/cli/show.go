@@ -0,0 +1,394 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// TaskDetails is everything 'todoist show' knows about a task
+type TaskDetails struct {
+	Task todoist.Task `json:"task"`
+	// Project is the path of project names from the top level project down
+	Project    string           `json:"project"`
+	Section    string           `json:"section,omitempty"`
+	Creator    *Person          `json:"creator,omitempty"`
+	Assignee   *Person          `json:"assignee,omitempty"`
+	AssignedBy *Person          `json:"assigned_by,omitempty"`
+	Subtasks   []todoist.Task   `json:"subtasks"`
+	Comments   []CommentDetails `json:"comments"`
+}
+
+// Person is a user referenced by a task, Name is empty if it could not be looked up
+type Person struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// CommentDetails is a comment with its author
+type CommentDetails struct {
+	todoist.Comment
+	Author *Person `json:"author,omitempty"`
+}
+
+// projectSeparator joins the names in a project path
+const projectSeparator = " / "
+
+var detailsTable = output.Table[TaskDetails]{
+	Default: []string{"id", "content", "project", "section", "creator", "assignee", "subtasks", "comments"},
+	Columns: []output.Column[TaskDetails]{
+		{Name: "id", Header: "ID", Value: func(d TaskDetails) string { return d.Task.ID }},
+		{Name: "content", Header: "Content", Value: func(d TaskDetails) string { return d.Task.Content }, Shrink: true},
+		{Name: "description", Header: "Description", Value: func(d TaskDetails) string { return d.Task.Description }, Shrink: true},
+		{Name: "project", Header: "Project", Value: func(d TaskDetails) string { return d.Project }, Shrink: true},
+		{Name: "section", Header: "Section", Value: func(d TaskDetails) string { return d.Section }, Shrink: true},
+		{Name: "priority", Header: "Pri", Value: func(d TaskDetails) string { return FormatPriority(d.Task.Priority) }},
+		{Name: "creator", Header: "Creator", Value: func(d TaskDetails) string { return d.Creator.String() }},
+		{Name: "assignee", Header: "Assignee", Value: func(d TaskDetails) string { return d.Assignee.String() }},
+		{Name: "subtasks", Header: "Subtasks", Value: func(d TaskDetails) string { return strconv.Itoa(len(d.Subtasks)) }},
+		{Name: "comments", Header: "Comments", Value: func(d TaskDetails) string { return strconv.Itoa(len(d.Comments)) }},
+	},
+}
+
+// String returns the name of the person, or their ID if the name is unknown
+func (p *Person) String() string {
+	switch {
+	case p == nil:
+		return ""
+	case p.Name != "":
+		return p.Name
+	}
+	return p.ID
+}
+
+// Show prints a task with its project path, people, subtasks and comments.
+// Tables get a readable view with the description rendered as Markdown, other formats and --columns the TaskDetails.
+func Show(ctx context.Context, client todoist.Client, id string, opts cache.Options, out output.Options) error {
+	details, err := LoadTaskDetails(ctx, client, id, opts)
+	if err != nil {
+		return err
+	}
+	if out.Structured() || len(out.Columns) > 0 {
+		return detailsTable.WriteOne(out, *details)
+	}
+	fmt.Print(renderDetails(details, out))
+	return nil
+}
+
+// LoadTaskDetails gathers a task from the cache, or from Todoist if it is not cached, with everything related to it.
+// Comments, names of people and completed subtasks need Todoist, so they are left out offline.
+func LoadTaskDetails(ctx context.Context, client todoist.Client, id string, opts cache.Options) (*TaskDetails, error) {
+	store, err := cache.Fetch(ctx, client, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+
+	task, ok := store.Task(id)
+	if !ok && !opts.Offline {
+		// Completed and uncached tasks are still available by ID
+		fetched, err := client.GetTask(ctx, id)
+		if errors.Is(err, todoist.ErrNotFound) {
+			return nil, fmt.Errorf("task %q not found", id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch task: %w", err)
+		}
+		task, ok = *fetched, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("task %q not found in the cache", id)
+	}
+
+	details := &TaskDetails{
+		Task:     task,
+		Project:  projectPath(store, task.ProjectID),
+		Subtasks: []todoist.Task{},
+		Comments: []CommentDetails{},
+	}
+	if task.SectionID != "" {
+		details.Section = task.SectionID
+		if section, err := store.FindSection("", task.SectionID); err == nil {
+			details.Section = section.Name
+		}
+	}
+
+	tasks, _ := store.Snapshot()
+	for _, t := range tasks {
+		if t.ParentID == task.ID {
+			details.Subtasks = append(details.Subtasks, t)
+		}
+	}
+	if !opts.Offline {
+		// The cache only holds open subtasks
+		completed, err := completedSubtasks(ctx, client, task.ID)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to fetch completed subtasks:", err)
+		}
+		for _, t := range completed {
+			if _, cached := store.Task(t.ID); !cached {
+				t.Checked = true
+				details.Subtasks = append(details.Subtasks, t)
+			}
+		}
+	}
+	query.Sort(details.Subtasks, []query.SortKey{{Field: "order"}}, nil)
+
+	if !opts.Offline {
+		comments, err := listComments(ctx, client, task.ID)
+		if err != nil {
+			// The comments are extra, the task itself is still worth showing
+			fmt.Fprintln(os.Stderr, "failed to fetch comments:", err)
+		}
+		for _, c := range comments {
+			details.Comments = append(details.Comments, CommentDetails{Comment: c})
+		}
+	}
+
+	people := lookupPeople(ctx, client, store, details, opts.Offline)
+	details.Creator = people.find(task.AddedByUID)
+	details.Assignee = people.find(task.ResponsibleUID)
+	details.AssignedBy = people.find(task.AssignedByUID)
+	for i := range details.Comments {
+		details.Comments[i].Author = people.find(details.Comments[i].PostedUID)
+	}
+	return details, nil
+}
+
+// completedSubtasks returns the subtasks of the task completed within cache.CompletedWindow
+func completedSubtasks(ctx context.Context, client todoist.Client, parentID string) ([]todoist.Task, error) {
+	now := time.Now()
+	options := todoist.CompletedTasksOptions{Since: now.Add(-cache.CompletedWindow), Until: now, ParentID: parentID, Limit: 200}
+	return todoist.ListAllCompletedTasks(ctx, client, options)
+}
+
+// projectPath joins the names of the project and its parents, top level first
+func projectPath(store *cache.Cache, projectID string) string {
+	var names []string
+	seen := make(map[string]bool)
+	for id := projectID; id != "" && !seen[id]; {
+		seen[id] = true
+		project, err := store.FindProject(id)
+		if err != nil {
+			names = append(names, id)
+			break
+		}
+		names = append(names, project.Name)
+		id = project.ParentID
+	}
+	slices.Reverse(names)
+	return strings.Join(names, projectSeparator)
+}
+
+func listComments(ctx context.Context, client todoist.Client, taskID string) ([]todoist.Comment, error) {
+	options := todoist.ListCommentsOptions{TaskID: taskID, Limit: 200}
+	var comments []todoist.Comment
+	for {
+		resp, err := client.ListComments(ctx, options)
+		if err != nil {
+			return comments, err
+		}
+		for _, c := range resp.Results {
+			if !c.IsDeleted {
+				comments = append(comments, c)
+			}
+		}
+		if resp.NextCursor == "" {
+			return comments, nil
+		}
+		options.Cursor = resp.NextCursor
+	}
+}
+
+// people maps user IDs to what is known about them
+type people map[string]Person
+
+func (p people) find(id string) *Person {
+	if id == "" {
+		return nil
+	}
+	if person, ok := p[id]; ok {
+		return &person
+	}
+	return &Person{ID: id}
+}
+
+// lookupPeople names the users referenced by the task and its comments: the logged in user,
+// and collaborators of the project if it is shared. Failed lookups leave users as bare IDs.
+func lookupPeople(ctx context.Context, client todoist.Client, store *cache.Cache, details *TaskDetails, offline bool) people {
+	found := make(people)
+	if offline {
+		return found
+	}
+
+	wanted := []string{details.Task.AddedByUID, details.Task.ResponsibleUID, details.Task.AssignedByUID}
+	for _, c := range details.Comments {
+		wanted = append(wanted, c.PostedUID)
+	}
+	missing := func() bool {
+		return slices.ContainsFunc(wanted, func(id string) bool {
+			_, ok := found[id]
+			return id != "" && !ok
+		})
+	}
+	if !missing() {
+		return found
+	}
+
+	if user, err := client.GetUser(ctx); err == nil {
+		found[user.ID] = Person{ID: user.ID, Name: user.FullName, Email: user.Email}
+	}
+	project, err := store.FindProject(details.Task.ProjectID)
+	if err != nil || !project.IsShared || !missing() {
+		return found
+	}
+
+	options := todoist.ListCollaboratorsOptions{ProjectID: project.ID, Limit: 200}
+	for {
+		resp, err := client.ListCollaborators(ctx, options)
+		if err != nil {
+			return found
+		}
+		for _, c := range resp.Results {
+			found[c.ID] = Person{ID: c.ID, Name: c.Name, Email: c.Email}
+		}
+		if resp.NextCursor == "" {
+			return found
+		}
+		options.Cursor = resp.NextCursor
+	}
+}
+
+var (
+	detailsTitle = lipgloss.NewStyle().Bold(true)
+	detailsLabel = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+)
+
+// renderDetails lays out a task for reading in the terminal
+func renderDetails(d *TaskDetails, out output.Options) string {
+	style := func(s lipgloss.Style, text string) string {
+		if !out.Color {
+			return text
+		}
+		return s.Render(text)
+	}
+	dateFormat := out.DateFormat
+	if dateFormat == "" {
+		dateFormat = "2006-01-02"
+	}
+
+	var b strings.Builder
+	field := func(label, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s %s\n", style(detailsLabel, fmt.Sprintf("%-11s", label+":")), value)
+		}
+	}
+
+	task := d.Task
+	title := renderInline(task.Content, out.Color, style)
+	if task.Checked {
+		title = "✓ " + title
+	}
+	b.WriteString(style(detailsTitle, title) + "\n\n")
+
+	field("ID", task.ID)
+	project := d.Project
+	if d.Section != "" {
+		project += projectSeparator + d.Section
+	}
+	field("Project", project)
+	field("Priority", FormatPriority(task.Priority))
+	field("Labels", strings.Join(task.Labels, ", "))
+	if task.Due != nil {
+		due := formatDue(task, dateFormat)
+		if recurring, _ := task.Due["is_recurring"].(bool); recurring {
+			if recurrence, _ := task.Due["string"].(string); recurrence != "" {
+				due += " (" + recurrence + ")"
+			}
+		}
+		field("Due", due)
+	}
+	field("Deadline", formatDateField(task.Deadline, dateFormat))
+	field("Duration", formatTaskDuration(task))
+	field("Created", withPerson(formatTimestamp(task.AddedAt, dateFormat), "by", d.Creator))
+	if d.Assignee != nil {
+		field("Assignee", withPerson(d.Assignee.String(), "assigned by", d.AssignedBy))
+	}
+	field("Updated", formatTimestamp(task.UpdatedAt, dateFormat))
+	field("Completed", formatTimestamp(task.CompletedAt, dateFormat))
+
+	if description := strings.TrimSpace(task.Description); description != "" {
+		b.WriteString("\n" + renderMarkdown(description, out.Color) + "\n")
+	}
+
+	if len(d.Subtasks) > 0 {
+		done := 0
+		for _, t := range d.Subtasks {
+			if t.Checked {
+				done++
+			}
+		}
+		fmt.Fprintf(&b, "\n%s\n", style(detailsTitle, fmt.Sprintf("Subtasks (%d/%d)", done, len(d.Subtasks))))
+		for _, t := range d.Subtasks {
+			mark := "○"
+			if t.Checked {
+				mark = "✓"
+			}
+			fmt.Fprintf(&b, "  %s %s  %s\n", mark, renderInline(t.Content, out.Color, style), style(detailsLabel, t.ID))
+		}
+	}
+
+	if len(d.Comments) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", style(detailsTitle, fmt.Sprintf("Comments (%d)", len(d.Comments))))
+		for _, c := range d.Comments {
+			fmt.Fprintf(&b, "\n  %s\n", style(detailsLabel, withPerson(formatTimestamp(c.PostedAt, dateFormat), "by", c.Author)))
+			if content := strings.TrimSpace(c.Content); content != "" {
+				for _, line := range strings.Split(renderMarkdown(content, out.Color), "\n") {
+					b.WriteString("  " + line + "\n")
+				}
+			}
+			if a := c.FileAttachment; a != nil {
+				name := a.FileName
+				if name == "" {
+					name = a.ResourceType
+				}
+				fmt.Fprintf(&b, "  📎 %s %s\n", name, a.FileURL)
+			}
+		}
+	}
+	return b.String()
+}
+
+// withPerson appends "by <name>" style attributions, leaving value alone when nobody is known
+func withPerson(value, verb string, p *Person) string {
+	if p == nil {
+		return value
+	}
+	if value == "" {
+		return verb + " " + p.String()
+	}
+	return value + " " + verb + " " + p.String()
+}
+
+// formatTimestamp shows an API timestamp in local time with layout, or as is if it cannot be parsed
+func formatTimestamp(ts, layout string) string {
+	if ts == "" {
+		return ""
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return ts
+	}
+	return parsed.Local().Format(layout + " 15:04")
+}
@@ -0,0 +1,353 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// statsBarWidth is the width of the bars in cells
+const statsBarWidth = 24
+
+// sparkBlocks draw sparklines, from the lowest value to the highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+var (
+	statsHeading = lipgloss.NewStyle().Bold(true)
+	statsMet     = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	statsBar     = lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
+	statsDim     = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+	statsUp      = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	statsDown    = lipgloss.NewStyle().Foreground(lipgloss.Color(overdueColor))
+)
+
+// StatsOptions controls where project names for the per-project breakdown come from
+type StatsOptions struct {
+	Cache cache.Options
+}
+
+var statsTable = output.Table[todoist.ProductivityStats]{
+	Default: []string{"karma", "trend", "completed", "today", "daily_goal", "daily_streak", "week", "weekly_goal", "weekly_streak"},
+	Columns: []output.Column[todoist.ProductivityStats]{
+		{Name: "karma", Header: "Karma", Value: func(s todoist.ProductivityStats) string { return formatKarma(s.Karma) }},
+		{Name: "trend", Header: "Trend", Value: func(s todoist.ProductivityStats) string { return s.KarmaTrend }},
+		{Name: "completed", Header: "Completed", Value: func(s todoist.ProductivityStats) string { return strconv.Itoa(s.CompletedCount) }},
+		{Name: "today", Header: "Today", Value: func(s todoist.ProductivityStats) string {
+			return strconv.Itoa(completedOn(s, time.Now().Format("2006-01-02")))
+		}},
+		{Name: "daily_goal", Header: "Daily goal", Value: func(s todoist.ProductivityStats) string { return strconv.Itoa(s.Goals.DailyGoal) }},
+		{Name: "daily_streak", Header: "Daily streak", Value: func(s todoist.ProductivityStats) string {
+			return strconv.Itoa(s.Goals.CurrentDailyStreak.Count)
+		}},
+		{Name: "week", Header: "This week", Value: func(s todoist.ProductivityStats) string {
+			if w, ok := currentWeek(s); ok {
+				return strconv.Itoa(w.TotalCompleted)
+			}
+			return "0"
+		}},
+		{Name: "weekly_goal", Header: "Weekly goal", Value: func(s todoist.ProductivityStats) string { return strconv.Itoa(s.Goals.WeeklyGoal) }},
+		{Name: "weekly_streak", Header: "Weekly streak", Value: func(s todoist.ProductivityStats) string {
+			return strconv.Itoa(s.Goals.CurrentWeeklyStreak.Count)
+		}},
+	},
+}
+
+// Stats prints completed tasks per day and week against the goals, streaks, karma and
+// the tasks completed per project. Other formats than tables write the stats as returned by Todoist.
+func Stats(ctx context.Context, client todoist.Client, opts StatsOptions, out output.Options) error {
+	if opts.Cache.Offline {
+		return fmt.Errorf("productivity stats are not cached, they need Todoist")
+	}
+	stats, err := client.GetProductivityStats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch productivity stats: %w", err)
+	}
+	if out.Structured() {
+		return statsTable.WriteOne(out, *stats)
+	}
+
+	store, err := cache.Fetch(ctx, client, opts.Cache)
+	if err != nil {
+		return fmt.Errorf("failed to fetch projects: %w", err)
+	}
+	renderStats(stats, store.Names().Projects, out, time.Now())
+	return nil
+}
+
+// SetGoals changes the karma goals, failing when options change nothing
+func SetGoals(ctx context.Context, client todoist.Client, options todoist.UpdateGoalsOptions) error {
+	cmd := todoist.UpdateGoalsCommand(options)
+	if len(cmd.Args) == 0 {
+		return fmt.Errorf("nothing to change, pass --daily, --weekly, --ignore-days, --vacation or --karma")
+	}
+	resp, err := client.Sync(ctx, todoist.SyncRequest{Commands: []todoist.Command{cmd}})
+	if err != nil {
+		return fmt.Errorf("failed to update goals: %w", err)
+	}
+	if msg := todoist.StatusError(resp.SyncStatus[cmd.UUID]); msg != "" {
+		return fmt.Errorf("failed to update goals: %s", msg)
+	}
+
+	var changed []string
+	if options.DailyGoal != nil {
+		changed = append(changed, fmt.Sprintf("daily goal %d", *options.DailyGoal))
+	}
+	if options.WeeklyGoal != nil {
+		changed = append(changed, fmt.Sprintf("weekly goal %d", *options.WeeklyGoal))
+	}
+	if options.IgnoreDays != nil {
+		changed = append(changed, "days off "+formatWeekdays(*options.IgnoreDays))
+	}
+	if options.VacationMode != nil {
+		changed = append(changed, "vacation mode "+onOff(*options.VacationMode))
+	}
+	if options.KarmaDisabled != nil {
+		changed = append(changed, "karma "+onOff(!*options.KarmaDisabled))
+	}
+	fmt.Println("Updated " + strings.Join(changed, ", "))
+	return nil
+}
+
+// renderStats writes the stats as headings followed by bar charts and sparklines
+func renderStats(stats *todoist.ProductivityStats, projects map[string]string, out output.Options, now time.Time) {
+	style := func(s lipgloss.Style, text string) string {
+		if !out.Color {
+			return text
+		}
+		return s.Render(text)
+	}
+	dateFormat := cmp.Or(out.DateFormat, "2006-01-02")
+	goals := stats.Goals
+
+	if goals.KarmaDisabled == 0 {
+		fmt.Println(style(statsHeading, "Karma"))
+		trend := stats.KarmaTrend
+		switch trend {
+		case "up":
+			trend = style(statsUp, "↑ up")
+		case "down":
+			trend = style(statsDown, "↓ down")
+		}
+		fmt.Printf("  %s  %s\n", formatKarma(stats.Karma), trend)
+		var karma []float64
+		for _, p := range stats.KarmaGraphData {
+			karma = append(karma, p.KarmaAvg)
+		}
+		if len(karma) > 1 {
+			first, last := stats.KarmaGraphData[0].Date, stats.KarmaGraphData[len(karma)-1].Date
+			fmt.Printf("  %s  %s\n", style(statsBar, sparkline(karma)), style(statsDim, formatDate(first, dateFormat)+" – "+formatDate(last, dateFormat)))
+		}
+		fmt.Println()
+	}
+
+	heading := "Daily goal"
+	if goals.VacationMode != 0 {
+		heading += " · vacation mode"
+	}
+	fmt.Println(style(statsHeading, heading))
+	fmt.Printf("  %s\n", streakLine(goals.DailyGoal, goals.CurrentDailyStreak, goals.MaxDailyStreak, "day"))
+	days := slices.Clone(stats.DaysItems)
+	slices.SortFunc(days, func(a, b todoist.DayStats) int { return strings.Compare(a.Date, b.Date) })
+	var rows [][2]string
+	var counts []float64
+	for _, d := range days {
+		label := formatDate(d.Date, "Mon "+dateFormat)
+		day, err := time.ParseInLocation("2006-01-02", d.Date, time.Local)
+		if err == nil && d.Date == now.Format("2006-01-02") {
+			label = "Today"
+		}
+		off := err == nil && slices.Contains(goals.IgnoreDays, isoWeekday(day))
+		rows = append(rows, [2]string{label, goalBar(d.TotalCompleted, goals.DailyGoal, off, style)})
+		counts = append(counts, float64(d.TotalCompleted))
+	}
+	writeChart(rows)
+	if len(counts) > 1 {
+		fmt.Printf("  %s\n", style(statsBar, sparkline(counts)))
+	}
+	fmt.Println()
+
+	fmt.Println(style(statsHeading, "Weekly goal"))
+	fmt.Printf("  %s\n", streakLine(goals.WeeklyGoal, goals.CurrentWeeklyStreak, goals.MaxWeeklyStreak, "week"))
+	weeks := slices.Clone(stats.WeekItems)
+	slices.SortFunc(weeks, func(a, b todoist.WeekStats) int { return strings.Compare(a.From, b.From) })
+	rows = nil
+	for _, w := range weeks {
+		label := formatDate(w.From, dateFormat) + " – " + formatDate(w.To, dateFormat)
+		rows = append(rows, [2]string{label, goalBar(w.TotalCompleted, goals.WeeklyGoal, false, style)})
+	}
+	writeChart(rows)
+
+	if breakdown := projectBreakdown(stats, projects); len(breakdown) > 0 {
+		fmt.Println()
+		fmt.Println(style(statsHeading, fmt.Sprintf("Completed by project, last %d days", len(stats.DaysItems))))
+		top := breakdown[0].count
+		rows = nil
+		for _, p := range breakdown {
+			rows = append(rows, [2]string{p.name, style(statsBar, bar(p.count, top)) + " " + strconv.Itoa(p.count)})
+		}
+		writeChart(rows)
+	}
+}
+
+// projectCount is the number of tasks completed in a project
+type projectCount struct {
+	name  string
+	count int
+}
+
+// projectBreakdown sums the tasks completed per project over the days, most first
+func projectBreakdown(stats *todoist.ProductivityStats, projects map[string]string) []projectCount {
+	totals := make(map[string]int)
+	for _, d := range stats.DaysItems {
+		for _, item := range d.Items {
+			totals[item.ID] += item.Completed
+		}
+	}
+	var breakdown []projectCount
+	for id, count := range totals {
+		if count > 0 {
+			breakdown = append(breakdown, projectCount{name: nameOr(projects, id), count: count})
+		}
+	}
+	slices.SortFunc(breakdown, func(a, b projectCount) int {
+		return cmp.Or(b.count-a.count, strings.Compare(a.name, b.name))
+	})
+	return breakdown
+}
+
+// writeChart writes rows of a label and a bar, aligning the bars
+func writeChart(rows [][2]string) {
+	width := 0
+	for _, r := range rows {
+		width = max(width, runewidth.StringWidth(r[0]))
+	}
+	for _, r := range rows {
+		fmt.Printf("  %s  %s\n", runewidth.FillRight(r[0], width), r[1])
+	}
+}
+
+// goalBar draws count against goal, highlighting met goals and dimming days off
+func goalBar(count, goal int, off bool, style func(lipgloss.Style, string) string) string {
+	if goal <= 0 {
+		return style(statsBar, bar(count, count)) + " " + strconv.Itoa(count)
+	}
+	text := fmt.Sprintf("%s %d/%d", bar(count, goal), count, goal)
+	switch {
+	case count >= goal:
+		return style(statsMet, text+" ✓")
+	case off:
+		return style(statsDim, text+" day off")
+	default:
+		return style(statsBar, text)
+	}
+}
+
+// bar draws value out of total as a bar statsBarWidth cells wide
+func bar(value, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = min(statsBarWidth, int(math.Round(float64(value)*statsBarWidth/float64(total))))
+	}
+	if value > 0 {
+		filled = max(filled, 1)
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", statsBarWidth-filled)
+}
+
+// sparkline draws values scaled between the lowest and the highest
+func sparkline(values []float64) string {
+	low, high := slices.Min(values), slices.Max(values)
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if high > low {
+			i = int((v - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
+
+// streakLine describes a goal with its current and longest streak of unit
+func streakLine(goal int, current, longest todoist.Streak, unit string) string {
+	if goal <= 0 {
+		return "No goal set"
+	}
+	return fmt.Sprintf("%d tasks a %s, streak %s, longest %s", goal, unit, plural(current.Count, unit), plural(longest.Count, unit))
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// completedOn is the number of tasks completed on date
+func completedOn(stats todoist.ProductivityStats, date string) int {
+	for _, d := range stats.DaysItems {
+		if d.Date == date {
+			return d.TotalCompleted
+		}
+	}
+	return 0
+}
+
+// currentWeek returns the most recent week of the stats
+func currentWeek(stats todoist.ProductivityStats) (todoist.WeekStats, bool) {
+	if len(stats.WeekItems) == 0 {
+		return todoist.WeekStats{}, false
+	}
+	return slices.MaxFunc(stats.WeekItems, func(a, b todoist.WeekStats) int { return strings.Compare(a.From, b.From) }), true
+}
+
+// formatDate reformats a YYYY-MM-DD date in layout, leaving other values as they are
+func formatDate(date, layout string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return t.Format(layout)
+}
+
+func formatKarma(karma float64) string {
+	return strconv.FormatFloat(karma, 'f', -1, 64)
+}
+
+// isoWeekday numbers the days of the week like Todoist does, 1 for Monday to 7 for Sunday
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+// formatWeekdays names the days of the week numbered 1 for Monday to 7 for Sunday
+func formatWeekdays(days []int) string {
+	if len(days) == 0 {
+		return "none"
+	}
+	names := make([]string, len(days))
+	for i, d := range days {
+		names[i] = time.Weekday(d % 7).String()[:3]
+	}
+	return strings.Join(names, ", ")
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
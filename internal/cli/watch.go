@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// Watch event types
+const (
+	EventAdded       = "added"
+	EventUpdated     = "updated"
+	EventCompleted   = "completed"
+	EventUncompleted = "uncompleted"
+	EventDeleted     = "deleted"
+)
+
+// eventColors tell the event types apart in human-readable lines
+var eventColors = map[string]string{
+	EventAdded:       "10",
+	EventCompleted:   "245",
+	EventUncompleted: "208",
+	EventDeleted:     overdueColor,
+}
+
+// WatchOptions selects the watched tasks, how often Todoist is polled and the hook run per event
+type WatchOptions struct {
+	Interval time.Duration
+	// Hook is a shell command run for every event with the task as JSON on stdin
+	Hook   string
+	Cache  cache.Options
+	Filter query.Filter
+	// Project and Section are names or IDs, resolved into Filter
+	Project string
+	Section string
+}
+
+// WatchEvent is a change to a task seen between two syncs
+type WatchEvent struct {
+	Type string       `json:"type"`
+	Time time.Time    `json:"time"`
+	Task todoist.Task `json:"task"`
+}
+
+// eventTable describes the event columns, showing project and section names from names
+func eventTable(names cache.Names) output.Table[WatchEvent] {
+	return output.Table[WatchEvent]{
+		Default: []string{"time", "type", "id", "content", "project"},
+		Columns: []output.Column[WatchEvent]{
+			{Name: "time", Header: "Time", Value: func(e WatchEvent) string { return e.Time.Format(time.RFC3339) }},
+			{Name: "type", Header: "Event", Value: func(e WatchEvent) string { return e.Type }},
+			{Name: "id", Header: "ID", Value: func(e WatchEvent) string { return e.Task.ID }},
+			{Name: "content", Header: "Content", Value: func(e WatchEvent) string { return e.Task.Content }},
+			{Name: "project", Header: "Project", Value: func(e WatchEvent) string { return nameOr(names.Projects, e.Task.ProjectID) }},
+			{Name: "section", Header: "Section", Value: func(e WatchEvent) string { return nameOr(names.Sections, e.Task.SectionID) }},
+			{Name: "parent", Header: "Parent", Value: func(e WatchEvent) string { return e.Task.ParentID }},
+		},
+	}
+}
+
+// Watch polls Todoist with incremental syncs every opts.Interval until ctx is done, writing an event for every
+// matching task that was added, updated, completed, uncompleted or deleted since the previous sync.
+// Tables get one line per event, JSON is written as NDJSON.
+func Watch(ctx context.Context, client todoist.Client, opts WatchOptions, out output.Options) error {
+	if opts.Cache.Offline {
+		return fmt.Errorf("watching needs Todoist, it cannot run offline")
+	}
+	store, err := cache.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load cache: %w", err)
+	}
+	// Catch up first so that only changes made from now on become events
+	if err := store.Refresh(ctx, client); errors.Is(err, cache.ErrNotSaved) {
+		fmt.Fprintln(os.Stderr, err)
+	} else if err != nil {
+		return err
+	}
+	filter := opts.Filter
+	list := ListOptions{Cache: opts.Cache, Project: opts.Project, Section: opts.Section}
+	if err := resolveFilter(ctx, client, store, list, &filter); err != nil {
+		return err
+	}
+	if out.Format == output.FormatJSON {
+		out.Format = output.FormatNDJSON
+	}
+
+	tasks, _ := store.Snapshot()
+	known := newWatched(tasks)
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		tasks, _ := store.Snapshot()
+		open := make(map[string]todoist.Task, len(tasks))
+		for _, t := range tasks {
+			open[t.ID] = t
+		}
+
+		changed, err := store.Changes(ctx, client)
+		switch {
+		case errors.Is(err, cache.ErrNotSaved):
+			// Todoist answered, so the changes are still news
+			fmt.Fprintln(os.Stderr, err)
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, "failed to refresh cache:", err)
+			continue
+		}
+
+		events := taskEvents(open, changed, known, filter, time.Now())
+		known.see(changed)
+		for _, e := range events {
+			if err := writeEvent(e, store.Names(), &out); err != nil {
+				return err
+			}
+			if opts.Hook != "" {
+				if err := runHook(ctx, opts.Hook, e); err != nil && ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "hook failed for %s task %s: %v\n", e.Type, e.Task.ID, err)
+				}
+			}
+		}
+	}
+}
+
+// watched is what a watch has seen of the tasks, so changes are told apart without the local clock
+type watched struct {
+	// seen holds the IDs of every task seen since the watch started, open or not
+	seen map[string]bool
+	// newest is the latest time Todoist reports a seen task was added at
+	newest time.Time
+}
+
+func newWatched(tasks []todoist.Task) *watched {
+	w := &watched{seen: make(map[string]bool)}
+	w.see(tasks)
+	return w
+}
+
+func (w *watched) see(tasks []todoist.Task) {
+	for _, t := range tasks {
+		w.seen[t.ID] = true
+		if added, err := time.Parse(time.RFC3339Nano, t.AddedAt); err == nil && added.After(w.newest) {
+			w.newest = added
+		}
+	}
+}
+
+// isNew reports whether t was added after every task seen so far, comparing Todoist's own timestamps.
+// Unseen tasks without a time they were added at count as new.
+func (w *watched) isNew(t todoist.Task) bool {
+	if w.seen[t.ID] {
+		return false
+	}
+	added, err := time.Parse(time.RFC3339Nano, t.AddedAt)
+	return err != nil || added.After(w.newest)
+}
+
+// taskEvents turns the tasks a sync reported as changed into events, given the tasks that were open before it
+// and those seen earlier. Tasks that were not open before count as added when they are new, and as added and
+// then completed when they are new but already completed. Other tasks that were not open are uncompleted.
+// Only tasks matching filter before or after the change make events.
+func taskEvents(open map[string]todoist.Task, changed []todoist.Task, known *watched, filter query.Filter, now time.Time) []WatchEvent {
+	var events []WatchEvent
+	for _, t := range changed {
+		old, wasOpen := open[t.ID]
+		isNew := known.isNew(t)
+
+		var kinds []string
+		switch {
+		case t.IsDeleted:
+			kinds = []string{EventDeleted}
+		case t.Checked && wasOpen:
+			kinds = []string{EventCompleted}
+		case t.Checked && isNew:
+			kinds = []string{EventAdded, EventCompleted}
+		case t.Checked:
+			// Completed tasks that change again are not news
+			continue
+		case wasOpen && reflect.DeepEqual(old, t):
+			continue
+		case wasOpen:
+			kinds = []string{EventUpdated}
+		case isNew:
+			kinds = []string{EventAdded}
+		default:
+			kinds = []string{EventUncompleted}
+		}
+
+		if filter.Match(t, now) || (wasOpen && filter.Match(old, now)) {
+			for _, kind := range kinds {
+				events = append(events, WatchEvent{Type: kind, Time: now, Task: t})
+			}
+		}
+	}
+	return events
+}
+
+// writeEvent writes one event, as a line for tables or through the table otherwise, leaving out headers after the first
+func writeEvent(e WatchEvent, names cache.Names, out *output.Options) error {
+	if out.Structured() {
+		err := eventTable(names).Write(*out, []WatchEvent{e})
+		out.NoHeader = true
+		return err
+	}
+
+	kind := fmt.Sprintf("%-11s", e.Type)
+	if out.Color {
+		kind = lipgloss.NewStyle().Foreground(lipgloss.Color(eventColors[e.Type])).Render(kind)
+	}
+	line := fmt.Sprintf("%s  %s  %s  %s", e.Time.Format("15:04:05"), kind, e.Task.ID, e.Task.Content)
+	if project, ok := names.Projects[e.Task.ProjectID]; ok {
+		line += "  #" + project
+	}
+	fmt.Println(line)
+	return nil
+}
+
+// runHook runs the shell command hook with the event's task as JSON on stdin and the event in the environment.
+// Its output goes to stderr so it stays apart from the events.
+func runHook(ctx context.Context, hook string, e WatchEvent) error {
+	data, err := json.Marshal(e.Task)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", hook)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	cmd.Env = append(os.Environ(), "TODOIST_EVENT="+e.Type, "TODOIST_TASK_ID="+e.Task.ID)
+	return cmd.Run()
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+func TestTaskEvents(t *testing.T) {
+	// The local clock is a day ahead of Todoist, events are told apart from Todoist's timestamps alone
+	now := time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC)
+	server := now.Add(-24 * time.Hour)
+	before, after := server.Add(-time.Hour).Format(time.RFC3339), server.Add(time.Minute).Format(time.RFC3339)
+
+	open := map[string]todoist.Task{
+		"same":     {ID: "same", Content: "Same", AddedAt: before},
+		"edited":   {ID: "edited", Content: "Old", AddedAt: before},
+		"done":     {ID: "done", Content: "Done", AddedAt: before},
+		"deleted":  {ID: "deleted", Content: "Deleted", AddedAt: before},
+		"moved":    {ID: "moved", Content: "Moved", ProjectID: "work", AddedAt: before},
+		"filtered": {ID: "filtered", Content: "Filtered", ProjectID: "home", AddedAt: before},
+		"latest":   {ID: "latest", Content: "Latest", AddedAt: server.Format(time.RFC3339)},
+	}
+	var tasks []todoist.Task
+	for _, t := range open {
+		tasks = append(tasks, t)
+	}
+	// Completed while watching, so seen but no longer open
+	completed := todoist.Task{ID: "completed", Content: "Completed", AddedAt: server.Add(time.Second).Format(time.RFC3339), Checked: true}
+	known := newWatched(append(tasks, completed))
+
+	tests := []struct {
+		name    string
+		changed todoist.Task
+		filter  query.Filter
+		want    []string
+	}{
+		{"unchanged", open["same"], query.Filter{}, nil},
+		{"updated", todoist.Task{ID: "edited", Content: "New", AddedAt: before}, query.Filter{}, []string{EventUpdated}},
+		{"completed", todoist.Task{ID: "done", Content: "Done", AddedAt: before, Checked: true}, query.Filter{}, []string{EventCompleted}},
+		{"deleted", todoist.Task{ID: "deleted", AddedAt: before, IsDeleted: true}, query.Filter{}, []string{EventDeleted}},
+		{"added", todoist.Task{ID: "new", Content: "New", AddedAt: after}, query.Filter{}, []string{EventAdded}},
+		{"uncompleted", todoist.Task{ID: "reopened", Content: "Reopened", AddedAt: before}, query.Filter{}, []string{EventUncompleted}},
+		{"added and completed", todoist.Task{ID: "quick", Content: "Quick", AddedAt: after, Checked: true}, query.Filter{}, []string{EventAdded, EventCompleted}},
+		{"completed task changed again", todoist.Task{ID: "old", Content: "Old", AddedAt: before, Checked: true}, query.Filter{}, nil},
+		{"seen completed task changed again", completed, query.Filter{}, nil},
+		{"seen completed task uncompleted", todoist.Task{ID: "completed", Content: "Completed", AddedAt: completed.AddedAt}, query.Filter{}, []string{EventUncompleted}},
+		{"added without a time", todoist.Task{ID: "untimed", Content: "Untimed"}, query.Filter{}, []string{EventAdded}},
+		{"moved out of the filter", todoist.Task{ID: "moved", Content: "Moved", ProjectID: "home", AddedAt: before}, query.Filter{ProjectID: "work"}, []string{EventUpdated}},
+		{"outside the filter", todoist.Task{ID: "filtered", Content: "Renamed", ProjectID: "home", AddedAt: before}, query.Filter{ProjectID: "work"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range taskEvents(open, []todoist.Task{tt.changed}, known, tt.filter, now) {
+				if e.Task.ID != tt.changed.ID || !e.Time.Equal(now) {
+					t.Errorf("event %+v is not about task %s at %v", e, tt.changed.ID, now)
+				}
+				got = append(got, e.Type)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("taskEvents() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
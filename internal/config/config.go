@@ -0,0 +1,20 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Dir returns the directory holding configuration, credentials and other local state,
+// $XDG_CONFIG_HOME/todoist or ~/.config/todoist
+func Dir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "todoist")
+	}
+	return filepath.Join(os.Getenv("HOME"), ".config", "todoist")
+}
+
+// Path returns the path of a file inside the config directory
+func Path(name string) string {
+	return filepath.Join(Dir(), name)
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultProfile keeps its files directly in the config directory for compatibility
+	DefaultProfile = "default"
+	// ProfileEnv names the environment variable selecting the active profile
+	ProfileEnv = "TODOIST_PROFILE"
+
+	currentProfileFile = "current_profile"
+)
+
+// profileFiles are the per-profile files removed with the default profile
+var profileFiles = []string{"credentials.json", "cache.json", "queue.json"}
+
+var (
+	validProfile = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+	// profileOverride is set from the --profile flag
+	profileOverride string
+)
+
+// SetProfile overrides the active profile, used for the --profile flag
+func SetProfile(name string) error {
+	if err := ValidateProfile(name); err != nil {
+		return err
+	}
+	profileOverride = name
+	return nil
+}
+
+// ValidateProfile checks that name is usable as a profile directory name
+func ValidateProfile(name string) error {
+	if !validProfile.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// Profile returns the active profile: the --profile flag, then TODOIST_PROFILE, then the saved current profile
+func Profile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	if name := os.Getenv(ProfileEnv); name != "" {
+		return name
+	}
+	return CurrentProfile()
+}
+
+// CurrentProfile returns the profile saved with SwitchProfile, or DefaultProfile
+func CurrentProfile() string {
+	data, err := os.ReadFile(Path(currentProfileFile))
+	if err != nil {
+		return DefaultProfile
+	}
+	name := strings.TrimSpace(string(data))
+	if ValidateProfile(name) != nil {
+		return DefaultProfile
+	}
+	return name
+}
+
+// ProfileDir returns the directory holding the active profile's credentials, cache and queue
+func ProfileDir() string {
+	return profileDir(Profile())
+}
+
+// ProfilePath returns the path of a file inside the active profile's directory
+func ProfilePath(name string) string {
+	return filepath.Join(ProfileDir(), name)
+}
+
+func profileDir(name string) string {
+	if name == DefaultProfile {
+		return Dir()
+	}
+	return filepath.Join(Dir(), "profiles", name)
+}
+
+// Profiles returns the names of all profiles that have credentials
+func Profiles() ([]string, error) {
+	var names []string
+	if HasProfile(DefaultProfile) {
+		names = append(names, DefaultProfile)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(Dir(), "profiles"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() && ValidateProfile(e.Name()) == nil && HasProfile(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// HasProfile reports whether the named profile has saved credentials
+func HasProfile(name string) bool {
+	_, err := os.Stat(filepath.Join(profileDir(name), "credentials.json"))
+	return err == nil
+}
+
+// SwitchProfile makes name the profile used when neither --profile nor TODOIST_PROFILE is set
+func SwitchProfile(name string) error {
+	if err := ValidateProfile(name); err != nil {
+		return err
+	}
+	if !HasProfile(name) {
+		return fmt.Errorf("profile %q does not exist, create it with: todoist auth --profile %s", name, name)
+	}
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(Path(currentProfileFile), []byte(name+"\n"), 0644)
+}
+
+// RemoveProfile deletes a profile's credentials, cache and queue, falling back to the default profile if it was current
+func RemoveProfile(name string) error {
+	if err := ValidateProfile(name); err != nil {
+		return err
+	}
+	if !HasProfile(name) {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+
+	if name == DefaultProfile {
+		for _, f := range profileFiles {
+			if err := os.Remove(filepath.Join(Dir(), f)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	} else if err := os.RemoveAll(profileDir(name)); err != nil {
+		return err
+	}
+
+	if CurrentProfile() == name {
+		if err := os.Remove(Path(currentProfileFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mdjarv/todoist-cli/internal/output"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the configuration file inside the config directory
+const FileName = "config.yaml"
+
+// Settings are the user-configurable options, at the top level of the config file or per profile
+type Settings struct {
+	ClientID       string `yaml:"client_id,omitempty"`
+	ClientSecret   string `yaml:"client_secret,omitempty"`
+	Scopes         string `yaml:"scopes,omitempty"`
+	DefaultProject string `yaml:"default_project,omitempty"`
+	Output         string `yaml:"output,omitempty"`
+	DateFormat     string `yaml:"date_format,omitempty"`
+	ReadOnly       string `yaml:"read_only,omitempty"`
+	// EncryptCredentials makes logins store the token encrypted with a passphrase
+	EncryptCredentials string      `yaml:"encrypt_credentials,omitempty"`
+	TUI                TUISettings `yaml:"tui,omitempty"`
+}
+
+// TUISettings configures the interactive task table
+type TUISettings struct {
+	Columns []string          `yaml:"columns,omitempty"`
+	Keys    map[string]string `yaml:"keys,omitempty"`
+}
+
+// File is the layout of config.yaml: global settings plus overrides per profile
+type File struct {
+	Settings `yaml:",inline"`
+	Profiles map[string]Settings `yaml:"profiles,omitempty"`
+}
+
+// Key describes one setting addressable with 'todoist config'
+type Key struct {
+	Name     string
+	Env      string
+	Default  string
+	Help     string
+	get      func(*Settings) string
+	set      func(*Settings, string)
+	validate func(string) error
+}
+
+// Validate checks value before it is stored
+func (k Key) Validate(value string) error {
+	if k.validate == nil || value == "" {
+		return nil
+	}
+	return k.validate(value)
+}
+
+// IsReadOnly reports whether the read_only setting is enabled
+func (s *Settings) IsReadOnly() bool {
+	readOnly, _ := strconv.ParseBool(s.ReadOnly)
+	return readOnly
+}
+
+// IsEncryptCredentials reports whether the encrypt_credentials setting is enabled
+func (s *Settings) IsEncryptCredentials() bool {
+	encrypt, _ := strconv.ParseBool(s.EncryptCredentials)
+	return encrypt
+}
+
+// Sources of a resolved setting, in increasing precedence
+const (
+	SourceDefault = "default"
+	SourceConfig  = "config"
+	SourceProfile = "profile"
+	SourceEnv     = "env"
+)
+
+// TUIColumns are the columns available in the TUI table
+var TUIColumns = []string{"done", "task", "project", "due", "labels"}
+
+// TUIActions are the TUI actions whose keys can be rebound with tui.keys.<action>
+var TUIActions = []string{"quit", "add", "toggle", "filter", "reload"}
+
+var defaultKeys = map[string]string{
+	"quit":   "q,ctrl+c",
+	"add":    "a",
+	"toggle": "enter",
+	"filter": "f",
+	"reload": "r",
+}
+
+// Keys lists every supported setting
+var Keys = buildKeys()
+
+func buildKeys() []Key {
+	keys := []Key{
+		{
+			Name: "client_id", Env: "TODOIST_CLIENT_ID",
+			Help: "OAuth client ID of your Todoist app",
+			get:  func(s *Settings) string { return s.ClientID },
+			set:  func(s *Settings, v string) { s.ClientID = v },
+		},
+		{
+			Name: "client_secret", Env: "TODOIST_CLIENT_SECRET",
+			Help: "OAuth client secret of your Todoist app",
+			get:  func(s *Settings) string { return s.ClientSecret },
+			set:  func(s *Settings, v string) { s.ClientSecret = v },
+		},
+		{
+			Name: "scopes", Env: "TODOIST_SCOPES", Default: todoist.DefaultScopes,
+			Help: "Comma separated OAuth scopes requested at login",
+			get:  func(s *Settings) string { return s.Scopes },
+			set:  func(s *Settings, v string) { s.Scopes = v },
+			validate: func(v string) error {
+				_, err := todoist.ParseScopes(v)
+				return err
+			},
+		},
+		{
+			Name: "default_project", Env: "TODOIST_DEFAULT_PROJECT",
+			Help: "Project name or ID new tasks go to, Inbox if empty",
+			get:  func(s *Settings) string { return s.DefaultProject },
+			set:  func(s *Settings, v string) { s.DefaultProject = v },
+		},
+		{
+			Name: "output", Env: "TODOIST_OUTPUT", Default: "table",
+			Help:     "Default output format of commands: table, json, ndjson, csv, tsv or yaml",
+			get:      func(s *Settings) string { return s.Output },
+			set:      func(s *Settings, v string) { s.Output = v },
+			validate: output.ValidateFormat,
+		},
+		{
+			Name: "date_format", Env: "TODOIST_DATE_FORMAT", Default: "2006-01-02",
+			Help: "Go time layout used to display dates",
+			get:  func(s *Settings) string { return s.DateFormat },
+			set:  func(s *Settings, v string) { s.DateFormat = v },
+		},
+		{
+			Name: "read_only", Env: "TODOIST_READ_ONLY", Default: "false",
+			Help: "Refuse every change to Todoist data, like --read-only",
+			get:  func(s *Settings) string { return s.ReadOnly },
+			set:  func(s *Settings, v string) { s.ReadOnly = v },
+			validate: func(v string) error {
+				_, err := strconv.ParseBool(v)
+				return err
+			},
+		},
+		{
+			Name: "encrypt_credentials", Env: "TODOIST_ENCRYPT_CREDENTIALS", Default: "false",
+			Help: "Store tokens encrypted with a passphrase when logging in, like auth --encrypt",
+			get:  func(s *Settings) string { return s.EncryptCredentials },
+			set:  func(s *Settings, v string) { s.EncryptCredentials = v },
+			validate: func(v string) error {
+				_, err := strconv.ParseBool(v)
+				return err
+			},
+		},
+		{
+			Name: "tui.columns", Default: "done,task,project,due,labels",
+			Help: "Comma separated TUI table columns",
+			get:  func(s *Settings) string { return strings.Join(s.TUI.Columns, ",") },
+			set: func(s *Settings, v string) {
+				s.TUI.Columns = splitList(v)
+			},
+			validate: func(v string) error {
+				for _, col := range splitList(v) {
+					if !slices.Contains(TUIColumns, col) {
+						return fmt.Errorf("unknown column %q, valid columns: %s", col, strings.Join(TUIColumns, ", "))
+					}
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, action := range TUIActions {
+		keys = append(keys, Key{
+			Name:    "tui.keys." + action,
+			Default: defaultKeys[action],
+			Help:    "Comma separated keys for the TUI " + action + " action",
+			get:     func(s *Settings) string { return s.TUI.Keys[action] },
+			set: func(s *Settings, v string) {
+				if s.TUI.Keys == nil {
+					s.TUI.Keys = make(map[string]string)
+				}
+				if v == "" {
+					delete(s.TUI.Keys, action)
+				} else {
+					s.TUI.Keys[action] = v
+				}
+			},
+		})
+	}
+	return keys
+}
+
+var (
+	currentOnce sync.Once
+	current     *Settings
+	currentErr  error
+)
+
+// Current returns the effective settings for the active profile, loaded once per run
+func Current() (*Settings, error) {
+	currentOnce.Do(func() {
+		var file *File
+		file, currentErr = LoadFile()
+		if currentErr != nil {
+			return
+		}
+		s := Settings{}
+		for _, k := range Keys {
+			value, source := file.Resolve(k)
+			if err := k.Validate(value); err != nil {
+				currentErr = fmt.Errorf("invalid %s from %s: %w", k.Name, source, err)
+				return
+			}
+			k.set(&s, value)
+		}
+		current = &s
+	})
+	return current, currentErr
+}
+
+// LookupKey returns the setting with the given name
+func LookupKey(name string) (Key, error) {
+	for _, k := range Keys {
+		if k.Name == name {
+			return k, nil
+		}
+	}
+	names := make([]string, len(Keys))
+	for i, k := range Keys {
+		names[i] = k.Name
+	}
+	sort.Strings(names)
+	return Key{}, fmt.Errorf("unknown setting %q, valid settings: %s", name, strings.Join(names, ", "))
+}
+
+// LoadFile reads config.yaml, returning an empty file if it does not exist
+func LoadFile() (*File, error) {
+	data, err := os.ReadFile(Path(FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseFile(data)
+}
+
+// ParseFile parses the contents of config.yaml
+func ParseFile(data []byte) (*File, error) {
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", Path(FileName), err)
+	}
+	return &file, nil
+}
+
+// Save writes the file back to config.yaml
+func (f *File) Save() error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(Path(FileName), data, 0600)
+}
+
+// Resolve returns the effective value of k for the active profile and where it came from.
+// Environment variables win over the profile section, which wins over the top level and defaults.
+func (f *File) Resolve(k Key) (string, string) {
+	if k.Env != "" {
+		if v := os.Getenv(k.Env); v != "" {
+			return v, SourceEnv
+		}
+	}
+	if p, ok := f.Profiles[Profile()]; ok {
+		if v := k.get(&p); v != "" {
+			return v, SourceProfile
+		}
+	}
+	if v := k.get(&f.Settings); v != "" {
+		return v, SourceConfig
+	}
+	return k.Default, SourceDefault
+}
+
+// Set stores value for k at the top level, or in the active profile's section when forProfile is set
+func (f *File) Set(k Key, value string, forProfile bool) {
+	if !forProfile {
+		k.set(&f.Settings, value)
+		return
+	}
+	if f.Profiles == nil {
+		f.Profiles = make(map[string]Settings)
+	}
+	p := f.Profiles[Profile()]
+	k.set(&p, value)
+	f.Profiles[Profile()] = p
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,305 @@
+// Package output renders command results as a table, JSON, NDJSON, CSV, TSV, YAML or a Go template
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	FormatTable  = "table"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+	FormatTSV    = "tsv"
+	FormatYAML   = "yaml"
+)
+
+// Formats are the supported values of --output
+var Formats = []string{FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatTSV, FormatYAML}
+
+// ValidateFormat checks an --output value
+func ValidateFormat(format string) error {
+	if !slices.Contains(Formats, format) {
+		return fmt.Errorf("unknown output format %q, valid formats: %s", format, strings.Join(Formats, ", "))
+	}
+	return nil
+}
+
+// Options select how results are written
+type Options struct {
+	Format string
+	// Template is a text/template executed once per item, it overrides Format
+	Template string
+	// Columns are the column names to show, empty for the table's defaults
+	Columns  []string
+	NoHeader bool
+	// Color styles table headers and cells
+	Color bool
+	// DateFormat is the Go time layout dates are shown with in tables
+	DateFormat string
+	// Width fits the table into this many cells by truncating the widest columns, 0 for no limit
+	Width int
+	// Writer receives the output, os.Stdout if nil
+	Writer io.Writer
+}
+
+// Structured reports whether the format is meant for programs rather than people
+func (o Options) Structured() bool {
+	return o.Template != "" || o.Format != FormatTable
+}
+
+func (o Options) writer() io.Writer {
+	if o.Writer == nil {
+		return os.Stdout
+	}
+	return o.Writer
+}
+
+// Column is one field of a result
+type Column[T any] struct {
+	// Name selects the column with --columns and is its key in JSON and YAML
+	Name   string
+	Header string
+	Value  func(T) string
+	// Color returns the table cell's foreground color, nil or "" for none
+	Color func(T) string
+	// Shrink lets the table truncate this column to fit the width, any column shrinks if none is marked
+	Shrink bool
+}
+
+// Table describes how items of one type are written
+type Table[T any] struct {
+	Columns []Column[T]
+	// Default are the column names shown when no columns were chosen
+	Default []string
+}
+
+// Write writes every item, JSON and YAML as a list
+func (t Table[T]) Write(opts Options, items []T) error {
+	return t.write(opts, items, false)
+}
+
+// WriteOne writes a single item, JSON and YAML as an object rather than a list
+func (t Table[T]) WriteOne(opts Options, item T) error {
+	return t.write(opts, []T{item}, true)
+}
+
+func (t Table[T]) write(opts Options, items []T, single bool) error {
+	w := opts.writer()
+	if opts.Template != "" {
+		return writeTemplate(w, opts.Template, items)
+	}
+
+	columns, err := t.selectColumns(opts)
+	if err != nil {
+		return err
+	}
+
+	switch opts.Format {
+	case FormatTable:
+		return writeTable(w, opts, columns, items)
+	case FormatCSV, FormatTSV:
+		return writeDelimited(w, opts, columns, items)
+	case FormatJSON, FormatNDJSON, FormatYAML:
+		// Whole items unless columns were chosen, then only those fields
+		values := make([]any, len(items))
+		for i, item := range items {
+			values[i] = item
+			if len(opts.Columns) > 0 {
+				values[i] = newRecord(columns, item)
+			}
+		}
+		return writeStructured(w, opts.Format, values, single)
+	}
+	return ValidateFormat(opts.Format)
+}
+
+func (t Table[T]) selectColumns(opts Options) ([]Column[T], error) {
+	names := opts.Columns
+	if len(names) == 0 {
+		names = t.Default
+	}
+	columns := make([]Column[T], 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(t.Columns, func(c Column[T]) bool { return c.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %q, valid columns: %s", name, strings.Join(t.names(), ", "))
+		}
+		columns = append(columns, t.Columns[i])
+	}
+	return columns, nil
+}
+
+func (t Table[T]) names() []string {
+	names := make([]string, len(t.Columns))
+	for i, c := range t.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func writeTemplate[T any](w io.Writer, text string, items []T) error {
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	for _, item := range items {
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, item); err != nil {
+			return fmt.Errorf("failed to execute template: %w", err)
+		}
+		if !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+			b.WriteByte('\n')
+		}
+		if _, err := w.Write(b.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func writeDelimited[T any](w io.Writer, opts Options, columns []Column[T], items []T) error {
+	cw := csv.NewWriter(w)
+	if opts.Format == FormatTSV {
+		cw.Comma = '\t'
+	}
+	clean := func(s string) string {
+		if opts.Format == FormatTSV {
+			// TSV has no quoting, so tabs and line breaks would split the record
+			return strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ").Replace(s)
+		}
+		return s
+	}
+
+	if !opts.NoHeader {
+		header := make([]string, len(columns))
+		for i, c := range columns {
+			header[i] = c.Name
+		}
+		cw.Write(header)
+	}
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = clean(c.Value(item))
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeStructured(w io.Writer, format string, values []any, single bool) error {
+	switch format {
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, v := range values {
+			if err := enc.Encode(v); err != nil {
+				return fmt.Errorf("failed to marshal to JSON: %w", err)
+			}
+		}
+		return nil
+	case FormatYAML:
+		var v any = values
+		if single {
+			v = values[0]
+		}
+		node, err := yamlNode(v)
+		if err != nil {
+			return err
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(node); err != nil {
+			return fmt.Errorf("failed to marshal to YAML: %w", err)
+		}
+		return enc.Close()
+	}
+
+	var v any = values
+	if single {
+		v = values[0]
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal to JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// record is an item reduced to the chosen columns, keeping their order when marshalled
+type record []field
+
+type field struct {
+	name  string
+	value string
+}
+
+func newRecord[T any](columns []Column[T], item T) record {
+	r := make(record, len(columns))
+	for i, c := range columns {
+		r[i] = field{c.Name, c.Value(item)}
+	}
+	return r
+}
+
+func (r record) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range r {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(f.name)
+		value, _ := json.Marshal(f.value)
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// yamlNode converts v by way of JSON, so YAML keys follow the json struct tags of API types
+func yamlNode(v any) (*yaml.Node, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal to YAML: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to marshal to YAML: %w", err)
+	}
+	blockStyle(&doc)
+	return &doc, nil
+}
+
+// blockStyle drops the flow style and quoting JSON was parsed with, the encoder quotes where needed
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
@@ -0,0 +1,123 @@
+package output
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	// columnGap separates table columns
+	columnGap = "  "
+	// minColumnWidth is as narrow as fitting the table to the width makes a column
+	minColumnWidth = 8
+	ellipsis       = "…"
+)
+
+var headerStyle = lipgloss.NewStyle().Bold(true)
+
+// writeTable writes items as aligned columns, measuring wide and combining characters correctly
+func writeTable[T any](w io.Writer, opts Options, columns []Column[T], items []T) error {
+	rows := make([][]string, len(items))
+	for i, item := range items {
+		rows[i] = make([]string, len(columns))
+		for j, c := range columns {
+			rows[i][j] = singleLine(c.Value(item))
+		}
+	}
+
+	widths := make([]int, len(columns))
+	for j, c := range columns {
+		if !opts.NoHeader {
+			widths[j] = runewidth.StringWidth(c.Header)
+		}
+		for _, row := range rows {
+			widths[j] = max(widths[j], runewidth.StringWidth(row[j]))
+		}
+	}
+	if opts.Width > 0 {
+		shrink := make([]bool, len(columns))
+		anyShrink := slices.ContainsFunc(columns, func(c Column[T]) bool { return c.Shrink })
+		for j, c := range columns {
+			shrink[j] = c.Shrink || !anyShrink
+		}
+		fitWidths(widths, shrink, opts.Width-len(columnGap)*(len(columns)-1))
+	}
+
+	var b strings.Builder
+	writeRow := func(cells []string, style func(int) *lipgloss.Style) {
+		for j, cell := range cells {
+			last := j == len(cells)-1
+			cell = runewidth.Truncate(cell, widths[j], ellipsis)
+			pad := widths[j] - runewidth.StringWidth(cell)
+			if s := style(j); s != nil {
+				cell = s.Render(cell)
+			}
+			b.WriteString(cell)
+			if !last {
+				b.WriteString(strings.Repeat(" ", pad) + columnGap)
+			}
+		}
+		b.WriteByte('\n')
+	}
+
+	if !opts.NoHeader {
+		header := make([]string, len(columns))
+		for j, c := range columns {
+			header[j] = c.Header
+		}
+		writeRow(header, func(int) *lipgloss.Style {
+			if !opts.Color {
+				return nil
+			}
+			return &headerStyle
+		})
+	}
+	for i, row := range rows {
+		writeRow(row, func(j int) *lipgloss.Style {
+			if !opts.Color || columns[j].Color == nil {
+				return nil
+			}
+			color := columns[j].Color(items[i])
+			if color == "" {
+				return nil
+			}
+			s := lipgloss.NewStyle().Foreground(lipgloss.Color(color))
+			return &s
+		})
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fitWidths narrows the widest shrinkable columns until they add up to at most total
+func fitWidths(widths []int, shrink []bool, total int) {
+	sum := 0
+	for _, w := range widths {
+		sum += w
+	}
+	for sum > total {
+		widest := -1
+		for j, w := range widths {
+			if shrink[j] && (widest < 0 || w > widths[widest]) {
+				widest = j
+			}
+		}
+		if widest < 0 || widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+		sum--
+	}
+}
+
+// lineBreaks would break a table row apart, tabs have no fixed width
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+
+func singleLine(s string) string {
+	return lineBreaks.Replace(s)
+}
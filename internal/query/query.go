@@ -0,0 +1,232 @@
+// Package query filters and sorts tasks, shared by the list command and the TUI
+package query
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// Filter selects tasks, zero fields match everything
+type Filter struct {
+	ProjectID string
+	SectionID string
+	ParentID  string
+	// Labels must all be present on the task
+	Labels []string
+	// Priority is the API priority, 4 for p1 down to 1 for p4
+	Priority int
+	// Due is today, tomorrow, overdue or a number of days ahead like 7d
+	Due   string
+	NoDue bool
+	// AssignedTo is the ID of the responsible user
+	AssignedTo string
+	// Search matches content and description, ignoring case
+	Search string
+}
+
+// ValidateDue checks a Filter.Due value
+func ValidateDue(due string) error {
+	_, _, err := dueRange(due, time.Now())
+	return err
+}
+
+// Apply returns the tasks matching f, keeping their order
+func Apply(tasks []todoist.Task, f Filter, now time.Time) []todoist.Task {
+	var matched []todoist.Task
+	for _, t := range tasks {
+		if f.Match(t, now) {
+			matched = append(matched, t)
+		}
+	}
+	return matched
+}
+
+// Match reports whether t passes every set field of f
+func (f Filter) Match(t todoist.Task, now time.Time) bool {
+	if f.ProjectID != "" && t.ProjectID != f.ProjectID {
+		return false
+	}
+	if f.SectionID != "" && t.SectionID != f.SectionID {
+		return false
+	}
+	if f.ParentID != "" && t.ParentID != f.ParentID {
+		return false
+	}
+	for _, label := range f.Labels {
+		if !slices.ContainsFunc(t.Labels, func(l string) bool { return strings.EqualFold(l, label) }) {
+			return false
+		}
+	}
+	if f.Priority != 0 && t.Priority != f.Priority {
+		return false
+	}
+	if f.AssignedTo != "" && t.ResponsibleUID != f.AssignedTo {
+		return false
+	}
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		if !strings.Contains(strings.ToLower(t.Content), search) && !strings.Contains(strings.ToLower(t.Description), search) {
+			return false
+		}
+	}
+
+	due, allDay, hasDue := dueTime(t)
+	if f.NoDue && hasDue {
+		return false
+	}
+	if f.Due != "" {
+		if !hasDue {
+			return false
+		}
+		from, until, err := dueRange(f.Due, now)
+		if allDay && f.Due == "overdue" {
+			// Date-only tasks are overdue from the next day, timed ones from their time
+			until = startOfDay(now)
+		}
+		if err != nil || due.Before(from) || !due.Before(until) {
+			return false
+		}
+	}
+	return true
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// dueRange returns the half-open time range a Filter.Due value selects
+func dueRange(due string, now time.Time) (time.Time, time.Time, error) {
+	today := startOfDay(now)
+	switch due {
+	case "today":
+		return today, today.AddDate(0, 0, 1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), nil
+	case "overdue":
+		return time.Time{}, now, nil
+	}
+	if days, ok := strings.CutSuffix(due, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return today, today.AddDate(0, 0, n), nil
+		}
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid due filter %q, use today, tomorrow, overdue or a number of days like 7d", due)
+}
+
+// DueTime returns when a task is due, date-only due dates at midnight local time
+func DueTime(t todoist.Task) (time.Time, bool) {
+	due, _, ok := dueTime(t)
+	return due, ok
+}
+
+// dueTime also reports whether the due date has no time of day
+func dueTime(t todoist.Task) (time.Time, bool, bool) {
+	if t.Due == nil {
+		return time.Time{}, false, false
+	}
+	date, ok := t.Due["date"].(string)
+	if !ok || date == "" {
+		return time.Time{}, false, false
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05"} {
+		if parsed, err := time.ParseInLocation(layout, date, time.Local); err == nil {
+			return parsed, false, true
+		}
+	}
+	if parsed, err := time.ParseInLocation("2006-01-02", date, time.Local); err == nil {
+		return parsed, true, true
+	}
+	return time.Time{}, false, false
+}
+
+// SortFields are the fields tasks can be sorted by
+var SortFields = []string{"due", "priority", "project", "created", "order", "day"}
+
+// SortKey is one field to sort by, prefix it with - in ParseSort for descending order
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses a comma separated list of sort fields like "due,-priority"
+func ParseSort(s string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key := SortKey{Field: field}
+		if f, ok := strings.CutPrefix(field, "-"); ok {
+			key = SortKey{Field: f, Desc: true}
+		}
+		if !slices.Contains(SortFields, key.Field) {
+			return nil, fmt.Errorf("unknown sort field %q, valid fields: %s", key.Field, strings.Join(SortFields, ", "))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Sort orders tasks by keys in turn, keeping the existing order of ties.
+// Tasks without a due date sort after those with one, also in descending order, and priority sorts p1 first.
+// Day is the manual order within a day in Todoist's Today view, tasks never ordered there sort last.
+func Sort(tasks []todoist.Task, keys []SortKey, projectNames map[string]string) {
+	slices.SortStableFunc(tasks, func(a, b todoist.Task) int {
+		for _, key := range keys {
+			if c := compare(a, b, key, projectNames); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+func compare(a, b todoist.Task, key SortKey, projectNames map[string]string) int {
+	order := func(c int) int {
+		if key.Desc {
+			return -c
+		}
+		return c
+	}
+	switch key.Field {
+	case "due":
+		dueA, okA := DueTime(a)
+		dueB, okB := DueTime(b)
+		switch {
+		case okA && okB:
+			return order(dueA.Compare(dueB))
+		case okA:
+			return -1
+		case okB:
+			return 1
+		}
+		return 0
+	case "priority":
+		return order(cmp.Compare(b.Priority, a.Priority))
+	case "project":
+		return order(cmp.Compare(strings.ToLower(projectNames[a.ProjectID]), strings.ToLower(projectNames[b.ProjectID])))
+	case "created":
+		return order(cmp.Compare(a.AddedAt, b.AddedAt))
+	case "order":
+		return order(cmp.Compare(a.ChildOrder, b.ChildOrder))
+	case "day":
+		// Todoist uses -1 for tasks without a day order
+		switch {
+		case a.DayOrder >= 0 && b.DayOrder >= 0:
+			return order(cmp.Compare(a.DayOrder, b.DayOrder))
+		case a.DayOrder >= 0:
+			return -1
+		case b.DayOrder >= 0:
+			return 1
+		}
+		return 0
+	}
+	return 0
+}
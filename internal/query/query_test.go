@@ -0,0 +1,176 @@
+package query
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// now is a Monday noon, in local time like the due dates Todoist sends without a timezone
+var now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local)
+
+func due(date string) todoist.Task {
+	return todoist.Task{ID: date, Due: map[string]any{"date": date}}
+}
+
+func TestMatchDue(t *testing.T) {
+	tests := []struct {
+		name string
+		due  string
+		task todoist.Task
+		want bool
+	}{
+		{"overdue date before today", "overdue", due("2026-10-18"), true},
+		{"overdue date today", "overdue", due("2026-10-19"), false},
+		{"overdue floating time earlier today", "overdue", due("2026-10-19T09:00:00"), true},
+		{"overdue floating time later today", "overdue", due("2026-10-19T15:00:00"), false},
+		{"overdue fixed time an hour ago", "overdue", due(now.Add(-time.Hour).UTC().Format(time.RFC3339)), true},
+		{"overdue fixed time in an hour", "overdue", due(now.Add(time.Hour).UTC().Format(time.RFC3339)), false},
+		{"overdue without due date", "overdue", todoist.Task{}, false},
+
+		{"today date", "today", due("2026-10-19"), true},
+		{"today floating time passed", "today", due("2026-10-19T09:00:00"), true},
+		{"today floating time to come", "today", due("2026-10-19T23:30:00"), true},
+		{"today date yesterday", "today", due("2026-10-18"), false},
+		{"today date tomorrow", "today", due("2026-10-20"), false},
+		{"today fixed time", "today", due(now.Add(time.Hour).UTC().Format(time.RFC3339)), true},
+
+		{"tomorrow date", "tomorrow", due("2026-10-20"), true},
+		{"tomorrow floating time", "tomorrow", due("2026-10-20T08:00:00"), true},
+		{"tomorrow date today", "tomorrow", due("2026-10-19"), false},
+		{"tomorrow date after", "tomorrow", due("2026-10-21"), false},
+
+		{"1d is today", "1d", due("2026-10-19"), true},
+		{"1d excludes tomorrow", "1d", due("2026-10-20"), false},
+		{"7d first day", "7d", due("2026-10-19"), true},
+		{"7d last day", "7d", due("2026-10-25T23:59:00"), true},
+		{"7d day after", "7d", due("2026-10-26"), false},
+		{"7d excludes overdue", "7d", due("2026-10-18"), false},
+		{"7d without due date", "7d", todoist.Task{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Filter{Due: tt.due}).Match(tt.task, now); got != tt.want {
+				t.Errorf("Match(%v) with due %q = %v, want %v", tt.task.Due, tt.due, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchFields(t *testing.T) {
+	task := todoist.Task{
+		Content:     "Buy Milk",
+		Description: "two litres",
+		ProjectID:   "p1",
+		Labels:      []string{"Shop", "errand"},
+		Priority:    4,
+		Due:         map[string]any{"date": "2026-10-19"},
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"zero filter", Filter{}, true},
+		{"project", Filter{ProjectID: "p1"}, true},
+		{"other project", Filter{ProjectID: "p2"}, false},
+		{"labels ignore case", Filter{Labels: []string{"shop", "ERRAND"}}, true},
+		{"all labels required", Filter{Labels: []string{"shop", "home"}}, false},
+		{"priority", Filter{Priority: 4}, true},
+		{"other priority", Filter{Priority: 1}, false},
+		{"search content", Filter{Search: "milk"}, true},
+		{"search description", Filter{Search: "LITRES"}, true},
+		{"search missing", Filter{Search: "bread"}, false},
+		{"no due", Filter{NoDue: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(task, now); got != tt.want {
+				t.Errorf("Match with %+v = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDue(t *testing.T) {
+	for _, valid := range []string{"today", "tomorrow", "overdue", "1d", "30d"} {
+		if err := ValidateDue(valid); err != nil {
+			t.Errorf("ValidateDue(%q) = %v, want nil", valid, err)
+		}
+	}
+	for _, invalid := range []string{"", "0d", "-1d", "d", "xd", "week", "7"} {
+		if err := ValidateDue(invalid); err == nil {
+			t.Errorf("ValidateDue(%q) = nil, want an error", invalid)
+		}
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []SortKey
+		wantErr bool
+	}{
+		{in: "", want: nil},
+		{in: "due", want: []SortKey{{Field: "due"}}},
+		{in: "due,-priority", want: []SortKey{{Field: "due"}, {Field: "priority", Desc: true}}},
+		{in: " project , ,-created ", want: []SortKey{{Field: "project"}, {Field: "created", Desc: true}}},
+		{in: "bogus", wantErr: true},
+		{in: "due,-bogus", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSort(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSort(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseSort(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	tasks := []todoist.Task{
+		{ID: "none-p1", Priority: 4, ProjectID: "b", DayOrder: -1},
+		{ID: "late-p4", Priority: 1, ProjectID: "a", Due: map[string]any{"date": "2026-10-21"}, DayOrder: 2},
+		{ID: "early-p2", Priority: 3, ProjectID: "b", Due: map[string]any{"date": "2026-10-19T08:00:00"}, DayOrder: 1},
+		{ID: "none-p4", Priority: 1, ProjectID: "a", DayOrder: -1},
+		{ID: "mid-p1", Priority: 4, ProjectID: "a", Due: map[string]any{"date": "2026-10-20"}, DayOrder: 0},
+	}
+	projects := map[string]string{"a": "Alpha", "b": "beta"}
+
+	tests := []struct {
+		sort string
+		want []string
+	}{
+		{"due", []string{"early-p2", "mid-p1", "late-p4", "none-p1", "none-p4"}},
+		{"-due", []string{"late-p4", "mid-p1", "early-p2", "none-p1", "none-p4"}},
+		{"priority", []string{"none-p1", "mid-p1", "early-p2", "late-p4", "none-p4"}},
+		{"-priority", []string{"late-p4", "none-p4", "early-p2", "none-p1", "mid-p1"}},
+		{"project,-due", []string{"late-p4", "mid-p1", "none-p4", "early-p2", "none-p1"}},
+		{"-project,priority", []string{"none-p1", "early-p2", "mid-p1", "late-p4", "none-p4"}},
+		{"day", []string{"mid-p1", "early-p2", "late-p4", "none-p1", "none-p4"}},
+		{"-day", []string{"late-p4", "early-p2", "mid-p1", "none-p1", "none-p4"}},
+		{"", []string{"none-p1", "late-p4", "early-p2", "none-p4", "mid-p1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			keys, err := ParseSort(tt.sort)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sorted := slices.Clone(tasks)
+			Sort(sorted, keys, projects)
+			var got []string
+			for _, task := range sorted {
+				got = append(got, task.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Sort by %q = %v, want %v", tt.sort, got, tt.want)
+			}
+		})
+	}
+}
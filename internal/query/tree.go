@@ -0,0 +1,119 @@
+package query
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// Tree glyphs drawn in front of subtasks
+const (
+	branchGlyph = "├─ "
+	lastGlyph   = "└─ "
+	pipeGlyph   = "│  "
+	spaceGlyph  = "   "
+)
+
+// collapsedMarker follows parents whose subtasks are hidden
+const collapsedMarker = " ▸"
+
+// Node is a task with its subtasks
+type Node struct {
+	Task     todoist.Task
+	Children []*Node
+}
+
+// BuildTree nests tasks under their parents. Subtasks follow child_order, while top level tasks,
+// including those whose parent is not among tasks, keep their order.
+func BuildTree(tasks []todoist.Task) []*Node {
+	nodes := make(map[string]*Node, len(tasks))
+	for _, t := range tasks {
+		nodes[t.ID] = &Node{Task: t}
+	}
+
+	var roots []*Node
+	for _, t := range tasks {
+		node := nodes[t.ID]
+		if parent, ok := nodes[t.ParentID]; ok && t.ParentID != t.ID {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	for _, node := range nodes {
+		slices.SortStableFunc(node.Children, func(a, b *Node) int {
+			return cmp.Compare(a.Task.ChildOrder, b.Task.ChildOrder)
+		})
+	}
+	return roots
+}
+
+// TreeRow is one line of a flattened tree
+type TreeRow struct {
+	Task todoist.Task
+	// Prefix draws the tree lines in front of the task
+	Prefix string
+	Depth  int
+	// Done and Total count the direct subtasks, Total is 0 for tasks without any
+	Done  int
+	Total int
+	// Collapsed is set on parents whose subtasks are hidden
+	Collapsed bool
+}
+
+// Content is the task content behind its tree lines, followed by the subtask counts
+func (r TreeRow) Content() string {
+	content := r.Prefix + r.Task.Content
+	if r.Total > 0 {
+		content += fmt.Sprintf(" (%d/%d)", r.Done, r.Total)
+	}
+	if r.Collapsed {
+		content += collapsedMarker
+	}
+	return content
+}
+
+// Flatten walks the tree depth first. Subtasks of collapsed tasks are left out,
+// as are tasks for which hide returns true together with their subtasks.
+func Flatten(roots []*Node, hide func(todoist.Task) bool) []TreeRow {
+	var rows []TreeRow
+	var walk func(nodes []*Node, depth int, indent string)
+	walk = func(nodes []*Node, depth int, indent string) {
+		var shown []*Node
+		for _, n := range nodes {
+			if hide == nil || !hide(n.Task) {
+				shown = append(shown, n)
+			}
+		}
+
+		for i, n := range shown {
+			row := TreeRow{Task: n.Task, Depth: depth, Total: len(n.Children)}
+			for _, c := range n.Children {
+				if c.Task.Checked {
+					row.Done++
+				}
+			}
+			row.Collapsed = n.Task.IsCollapsed && len(n.Children) > 0
+
+			last := i == len(shown)-1
+			childIndent := indent
+			if depth > 0 {
+				row.Prefix = indent + branchGlyph
+				childIndent = indent + pipeGlyph
+				if last {
+					row.Prefix = indent + lastGlyph
+					childIndent = indent + spaceGlyph
+				}
+			}
+			rows = append(rows, row)
+
+			if !row.Collapsed {
+				walk(n.Children, depth+1, childIndent)
+			}
+		}
+	}
+	walk(roots, 0, "")
+	return rows
+}
@@ -0,0 +1,132 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// ErrQueued is returned when a mutation could not reach Todoist and was queued for replay
+var ErrQueued = errors.New("offline: change queued and will be sent on the next sync")
+
+type client struct {
+	todoist.Client
+	journal *Journal
+	offline bool
+}
+
+// NewClient wraps a todoist.Client so mutations go through the journal.
+// Every mutation is recorded before it is sent, pending mutations are replayed
+// ahead of it, and when Todoist is unreachable, overloaded or rate limiting it
+// stays queued and ErrQueued is returned. Requests Todoist rejects as a whole
+// become conflicts, so they do not block later work. Offline clients only queue mutations.
+// Reads through Sync piggyback any pending mutations on the same request.
+func NewClient(inner todoist.Client, journal *Journal, offline bool) todoist.Client {
+	return &client{Client: inner, journal: journal, offline: offline}
+}
+
+// CreateTask returns the task built from the command, with its real ID once Todoist has it.
+// When queued the task keeps its temporary ID and is returned along with ErrQueued.
+func (c *client) CreateTask(ctx context.Context, options todoist.CreateTaskOptions) (*todoist.Task, error) {
+	cmd := todoist.AddTaskCommand(options)
+	result, err := c.do(ctx, cmd)
+	if err != nil && !errors.Is(err, ErrQueued) {
+		return nil, err
+	}
+
+	task := &todoist.Task{ID: cmd.TempID, DayOrder: -1}
+	applyArgs(task, cmd.Args)
+	if result != nil {
+		if id, ok := result.TempIDMapping[cmd.TempID]; ok {
+			task.ID = id
+		}
+	}
+	return task, err
+}
+
+func (c *client) UpdateTask(ctx context.Context, taskID string, options todoist.UpdateTaskOptions) error {
+	_, err := c.do(ctx, todoist.UpdateTaskCommand(taskID, options))
+	return err
+}
+
+func (c *client) MoveTask(ctx context.Context, taskID string, options todoist.MoveTaskOptions) error {
+	_, err := c.do(ctx, todoist.MoveTaskCommand(taskID, options))
+	return err
+}
+
+func (c *client) CloseTask(ctx context.Context, taskID string) error {
+	_, err := c.do(ctx, todoist.CloseTaskCommand(taskID))
+	return err
+}
+
+func (c *client) ReopenTask(ctx context.Context, taskID string) error {
+	_, err := c.do(ctx, todoist.ReopenTaskCommand(taskID))
+	return err
+}
+
+func (c *client) Sync(ctx context.Context, request todoist.SyncRequest) (*todoist.SyncResponse, error) {
+	if len(request.Commands) > 0 || c.journal.Len() == 0 {
+		return c.Client.Sync(ctx, request)
+	}
+
+	commands := c.journal.Commands()
+	if len(commands) > batchSize {
+		// Too many to piggyback, replay them on their own first
+		if _, err := c.journal.Push(ctx, c.Client); err != nil {
+			return nil, err
+		}
+		return c.Client.Sync(ctx, request)
+	}
+
+	request.Commands = commands
+	resp, err := c.Client.Sync(ctx, request)
+	if isRejected(err) {
+		// Drop the commands Todoist refused so they do not fail every later read
+		if _, err := c.journal.Reject(commands, err); err != nil {
+			return nil, err
+		}
+		request.Commands = nil
+		return c.Client.Sync(ctx, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.journal.Settle(commands, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *client) do(ctx context.Context, cmd todoist.Command) (*Result, error) {
+	if err := c.journal.Append(cmd); err != nil {
+		return nil, fmt.Errorf("failed to queue change: %w", err)
+	}
+
+	if c.offline {
+		return nil, ErrQueued
+	}
+
+	result, err := c.journal.Push(ctx, c.Client)
+	if err != nil {
+		if todoist.IsTemporaryError(err) {
+			return nil, ErrQueued
+		}
+		// Drop the change only when it was in the request that failed, older ones stay queued
+		if slices.ContainsFunc(result.Failed, func(failed todoist.Command) bool { return failed.UUID == cmd.UUID }) {
+			if err := c.journal.Remove(cmd.UUID); err != nil {
+				return nil, fmt.Errorf("failed to drop change from the queue: %w", err)
+			}
+		}
+		return nil, err
+	}
+
+	for _, conflict := range result.Conflicts {
+		if conflict.Mutation.UUID == cmd.UUID {
+			return nil, fmt.Errorf("todoist rejected change: %s", conflict.Error)
+		}
+	}
+	return result, nil
+}
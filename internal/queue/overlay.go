@@ -0,0 +1,104 @@
+package queue
+
+import "github.com/mdjarv/todoist-cli/internal/todoist"
+
+// Apply returns tasks with the pending mutations applied, so queued changes show up before they are synced
+func (j *Journal) Apply(tasks []todoist.Task) []todoist.Task {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	index := make(map[string]int, len(tasks))
+	for i, t := range tasks {
+		index[t.ID] = i
+	}
+
+	for _, m := range j.Pending {
+		if m.Type == "item_add" {
+			// Todoist gives a task its day order once it has it, until then it has none like undated tasks
+			task := todoist.Task{ID: m.TempID, DayOrder: -1}
+			applyArgs(&task, m.Args)
+			index[task.ID] = len(tasks)
+			tasks = append(tasks, task)
+			continue
+		}
+
+		id, _ := m.Args["id"].(string)
+		i, ok := index[id]
+		if !ok {
+			continue
+		}
+		switch m.Type {
+		case "item_close":
+			tasks[i].Checked = true
+		case "item_uncomplete":
+			tasks[i].Checked = false
+		case "item_update", "item_move":
+			applyArgs(&tasks[i], m.Args)
+		}
+	}
+	return tasks
+}
+
+// PendingIDs returns the IDs of tasks with queued mutations, including temporary IDs of queued new tasks
+func (j *Journal) PendingIDs() map[string]bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	ids := make(map[string]bool)
+	for _, m := range j.Pending {
+		if m.TempID != "" {
+			ids[m.TempID] = true
+		}
+		if id, ok := m.Args["id"].(string); ok {
+			ids[id] = true
+		}
+	}
+	return ids
+}
+
+func applyArgs(task *todoist.Task, args map[string]any) {
+	if v, ok := args["content"].(string); ok {
+		task.Content = v
+	}
+	if v, ok := args["description"].(string); ok {
+		task.Description = v
+	}
+	if v, ok := args["project_id"].(string); ok {
+		task.ProjectID = v
+	}
+	if v, ok := args["section_id"].(string); ok {
+		task.SectionID = v
+	}
+	if v, ok := args["parent_id"].(string); ok {
+		task.ParentID = v
+	}
+	if v, ok := args["responsible_uid"]; ok {
+		task.ResponsibleUID, _ = v.(string)
+	}
+	if v, ok := args["priority"].(float64); ok {
+		task.Priority = int(v)
+	}
+	if v, ok := args["priority"].(int); ok {
+		task.Priority = v
+	}
+	if v, ok := args["labels"].([]string); ok {
+		task.Labels = v
+	}
+	if v, ok := args["labels"].([]any); ok {
+		task.Labels = nil
+		for _, l := range v {
+			if s, ok := l.(string); ok {
+				task.Labels = append(task.Labels, s)
+			}
+		}
+	}
+	if v, ok := args["due"]; ok {
+		task.Due, _ = v.(map[string]any)
+	}
+	if v, ok := args["deadline"]; ok {
+		task.Deadline, _ = v.(map[string]any)
+	}
+	if v, ok := args["duration"]; ok {
+		task.Duration, _ = v.(map[string]any)
+	}
+}
@@ -0,0 +1,253 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+const (
+	fileName = "queue.json"
+	// batchSize is the maximum number of commands Todoist accepts in one Sync request
+	batchSize = 100
+)
+
+// Mutation is a Sync command waiting to be sent to Todoist
+type Mutation struct {
+	todoist.Command
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+// Conflict is a queued mutation that Todoist rejected on replay
+type Conflict struct {
+	Mutation Mutation `json:"mutation"`
+	Error    string   `json:"error"`
+}
+
+// Result summarises a push of queued mutations
+type Result struct {
+	Applied   int
+	Conflicts []Conflict
+	// TempIDMapping maps temporary IDs of created items to their real IDs
+	TempIDMapping map[string]string
+	// Failed are the commands of the request a push stopped at, still pending
+	Failed []todoist.Command
+}
+
+// Journal is the durable on-disk list of mutations that have not reached Todoist yet
+type Journal struct {
+	Pending   []Mutation `json:"pending"`
+	Conflicts []Conflict `json:"conflicts"`
+
+	mu sync.Mutex
+}
+
+// Load reads the journal from disk, returning an empty journal if none exists
+func Load() (*Journal, error) {
+	data, err := os.ReadFile(config.ProfilePath(fileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Journal{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var j Journal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("failed to parse queue: %w", err)
+	}
+	return &j, nil
+}
+
+// Append durably records a command before it is sent
+func (j *Journal) Append(cmd todoist.Command) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Pending = append(j.Pending, Mutation{Command: cmd, QueuedAt: time.Now()})
+	return j.save()
+}
+
+// Remove drops a pending command, used when it failed for a reason other than being offline
+func (j *Journal) Remove(uuid string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Pending = without(j.Pending, map[string]bool{uuid: true})
+	return j.save()
+}
+
+// Len returns the number of pending mutations
+func (j *Journal) Len() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.Pending)
+}
+
+// Commands returns the pending mutations as Sync commands, oldest first
+func (j *Journal) Commands() []todoist.Command {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	commands := make([]todoist.Command, len(j.Pending))
+	for i, m := range j.Pending {
+		commands[i] = m.Command
+	}
+	return commands
+}
+
+// TakeConflicts returns and clears the conflicts recorded since the last call
+func (j *Journal) TakeConflicts() ([]Conflict, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	conflicts := j.Conflicts
+	if len(conflicts) == 0 {
+		return nil, nil
+	}
+	j.Conflicts = nil
+	return conflicts, j.save()
+}
+
+// Push replays all pending mutations in batches, stopping at the first failed request.
+// A request Todoist rejects as a whole has its commands recorded as conflicts instead.
+func (j *Journal) Push(ctx context.Context, client todoist.Client) (*Result, error) {
+	result := &Result{TempIDMapping: make(map[string]string)}
+	commands := j.Commands()
+	for len(commands) > 0 {
+		n := min(batchSize, len(commands))
+		var batch *Result
+		resp, err := client.Sync(ctx, todoist.SyncRequest{Commands: commands[:n]})
+		switch {
+		case isRejected(err):
+			batch, err = j.Reject(commands[:n], err)
+		case err != nil:
+			result.Failed = commands[:n]
+			return result, err
+		default:
+			batch, err = j.Settle(commands[:n], resp)
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Applied += batch.Applied
+		result.Conflicts = append(result.Conflicts, batch.Conflicts...)
+		maps.Copy(result.TempIDMapping, batch.TempIDMapping)
+		commands = commands[n:]
+	}
+	return result, nil
+}
+
+// Settle removes the commands Todoist answered for in resp, recording rejected ones as conflicts
+func (j *Journal) Settle(commands []todoist.Command, resp *todoist.SyncResponse) (*Result, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	sent := make(map[string]bool, len(commands))
+	for _, cmd := range commands {
+		sent[cmd.UUID] = true
+	}
+
+	result := &Result{TempIDMapping: resp.TempIDMapping}
+	done := make(map[string]bool)
+	for _, m := range j.Pending {
+		if !sent[m.UUID] {
+			continue
+		}
+		status, ok := resp.SyncStatus[m.UUID]
+		if !ok {
+			continue
+		}
+		done[m.UUID] = true
+		if msg := todoist.StatusError(status); msg != "" {
+			conflict := Conflict{Mutation: m, Error: msg}
+			result.Conflicts = append(result.Conflicts, conflict)
+			j.Conflicts = append(j.Conflicts, conflict)
+		} else {
+			result.Applied++
+		}
+	}
+
+	j.Pending = without(j.Pending, done)
+	return result, j.save()
+}
+
+// Reject records commands Todoist refused as a whole request as conflicts and drops them
+func (j *Journal) Reject(commands []todoist.Command, reason error) (*Result, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	sent := make(map[string]bool, len(commands))
+	for _, cmd := range commands {
+		sent[cmd.UUID] = true
+	}
+
+	result := &Result{}
+	for _, m := range j.Pending {
+		if sent[m.UUID] {
+			conflict := Conflict{Mutation: m, Error: reason.Error()}
+			result.Conflicts = append(result.Conflicts, conflict)
+			j.Conflicts = append(j.Conflicts, conflict)
+		}
+	}
+
+	j.Pending = without(j.Pending, sent)
+	return result, j.save()
+}
+
+// isRejected reports whether Todoist refused a request as a whole, so sending it again would fail the same way
+func isRejected(err error) bool {
+	var apiErr *todoist.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusBadRequest || apiErr.StatusCode == http.StatusForbidden
+	}
+	var scopeErr *todoist.ScopeError
+	return errors.As(err, &scopeErr)
+}
+
+// save writes the journal atomically so a crash never leaves a truncated file
+func (j *Journal) save() error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(config.ProfileDir(), 0755); err != nil {
+		return err
+	}
+
+	path := config.ProfilePath(fileName)
+	tmp, err := os.CreateTemp(config.ProfileDir(), fileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func without(mutations []Mutation, uuids map[string]bool) []Mutation {
+	var kept []Mutation
+	for _, m := range mutations {
+		if !uuids[m.UUID] {
+			kept = append(kept, m)
+		}
+	}
+	return kept
+}
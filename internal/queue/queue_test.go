@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mdjarv/todoist-cli/internal/todoist"
+)
+
+// syncClient answers Sync requests with a status per command, failing the requests listed in fail
+type syncClient struct {
+	todoist.Client
+	requests []todoist.SyncRequest
+	// fail maps the index of a request to the error it fails with
+	fail map[int]error
+}
+
+func (c *syncClient) Sync(ctx context.Context, request todoist.SyncRequest) (*todoist.SyncResponse, error) {
+	c.requests = append(c.requests, request)
+	if err := c.fail[len(c.requests)-1]; err != nil {
+		return nil, err
+	}
+	resp := &todoist.SyncResponse{SyncStatus: make(map[string]json.RawMessage), TempIDMapping: make(map[string]string)}
+	for _, cmd := range request.Commands {
+		resp.SyncStatus[cmd.UUID] = json.RawMessage(`"ok"`)
+	}
+	return resp, nil
+}
+
+// queued returns a journal holding n close commands, stored in a temporary profile
+func queued(t *testing.T, n int) *Journal {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("TODOIST_PROFILE", "")
+	j := &Journal{}
+	for range n {
+		if err := j.Append(todoist.CloseTaskCommand("1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return j
+}
+
+func TestPushRejectedBatch(t *testing.T) {
+	j := queued(t, batchSize+1)
+	inner := &syncClient{fail: map[int]error{0: &todoist.APIError{StatusCode: http.StatusBadRequest, Status: "Bad Request"}}}
+
+	result, err := j.Push(context.Background(), inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Applied != 1 || len(result.Conflicts) != batchSize {
+		t.Errorf("Push() applied %d with %d conflicts, want 1 and %d", result.Applied, len(result.Conflicts), batchSize)
+	}
+	if j.Len() != 0 || len(j.Conflicts) != batchSize {
+		t.Errorf("journal has %d pending and %d conflicts, want none and %d", j.Len(), len(j.Conflicts), batchSize)
+	}
+}
+
+func TestClientRejectedBatch(t *testing.T) {
+	j := queued(t, batchSize)
+	inner := &syncClient{fail: map[int]error{0: &todoist.APIError{StatusCode: http.StatusBadRequest, Status: "Bad Request"}}}
+	c := NewClient(inner, j, false)
+
+	// The queued changes fill the first request and are rejected, the new one is sent after them
+	if err := c.CloseTask(context.Background(), "2"); err != nil {
+		t.Fatalf("CloseTask() = %v, want the change sent after the rejected one", err)
+	}
+	if len(inner.requests) != 2 || len(inner.requests[1].Commands) != 1 {
+		t.Fatalf("sent %d requests, want the rejected one and one with the new change", len(inner.requests))
+	}
+	if j.Len() != 0 || len(j.Conflicts) != batchSize {
+		t.Errorf("journal has %d pending and %d conflicts, want none and %d", j.Len(), len(j.Conflicts), batchSize)
+	}
+
+	// Later reads no longer carry the rejected change
+	if _, err := c.Sync(context.Background(), todoist.SyncRequest{SyncToken: "*"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(inner.requests[2].Commands) != 0 {
+		t.Errorf("read sent %d commands, want none", len(inner.requests[2].Commands))
+	}
+}
+
+func TestClientSyncDropsRejectedCommands(t *testing.T) {
+	j := queued(t, 2)
+	inner := &syncClient{fail: map[int]error{0: &todoist.APIError{StatusCode: http.StatusForbidden, Status: "Forbidden"}}}
+	c := NewClient(inner, j, false)
+
+	if _, err := c.Sync(context.Background(), todoist.SyncRequest{SyncToken: "*"}); err != nil {
+		t.Fatalf("Sync() = %v, want the read retried without the rejected commands", err)
+	}
+	if len(inner.requests) != 2 || len(inner.requests[1].Commands) != 0 {
+		t.Errorf("sent %d requests, want the rejected one and a read without commands", len(inner.requests))
+	}
+	if j.Len() != 0 || len(j.Conflicts) != 2 {
+		t.Errorf("journal has %d pending and %d conflicts, want none and 2", j.Len(), len(j.Conflicts))
+	}
+}
+
+func TestClientKeepsOlderChangesOnOtherErrors(t *testing.T) {
+	j := queued(t, batchSize)
+	unauthorized := &todoist.APIError{StatusCode: http.StatusUnauthorized, Status: "Unauthorized"}
+	inner := &syncClient{fail: map[int]error{0: unauthorized}}
+	c := NewClient(inner, j, false)
+
+	// The new change lands in a second request that is never sent, so it stays queued
+	if err := c.CloseTask(context.Background(), "2"); !errors.Is(err, unauthorized) {
+		t.Fatalf("CloseTask() = %v, want %v", err, unauthorized)
+	}
+	if j.Len() != batchSize+1 {
+		t.Errorf("journal has %d pending, want %d", j.Len(), batchSize+1)
+	}
+
+	// Once the older changes are sent, the new change is in the failed request and is dropped
+	inner.fail = map[int]error{2: unauthorized}
+	if err := c.CloseTask(context.Background(), "3"); !errors.Is(err, unauthorized) {
+		t.Fatalf("CloseTask() = %v, want %v", err, unauthorized)
+	}
+	if j.Len() != 1 {
+		t.Errorf("journal has %d pending, want only the change queued before the failed request", j.Len())
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 const (
@@ -18,11 +20,22 @@ const (
 
 type Client interface {
 	ListTasks(ctx context.Context, options *ListTasksOptions) (*TasksResponse, error)
+	ListCompletedTasks(ctx context.Context, options CompletedTasksOptions) (*CompletedTasksResponse, error)
 	ListProjects(ctx context.Context) (*ProjectsResponse, error)
 	ExchangeCodeForToken(code, redirectURI, clientID, clientSecret string) (*TokenResponse, error)
+	RevokeToken(ctx context.Context, clientID, clientSecret, accessToken string) error
+	MigratePersonalToken(ctx context.Context, personalToken, clientID, clientSecret, scope string) (*TokenResponse, error)
 	CloseTask(ctx context.Context, taskID string) error
 	ReopenTask(ctx context.Context, taskID string) error
-	CreateTask(ctx context.Context, options CreateTaskOptions) error
+	CreateTask(ctx context.Context, options CreateTaskOptions) (*Task, error)
+	UpdateTask(ctx context.Context, taskID string, options UpdateTaskOptions) error
+	MoveTask(ctx context.Context, taskID string, options MoveTaskOptions) error
+	Sync(ctx context.Context, request SyncRequest) (*SyncResponse, error)
+	GetUser(ctx context.Context) (*User, error)
+	GetTask(ctx context.Context, taskID string) (*Task, error)
+	ListComments(ctx context.Context, options ListCommentsOptions) (*CommentsResponse, error)
+	ListCollaborators(ctx context.Context, options ListCollaboratorsOptions) (*CollaboratorsResponse, error)
+	GetProductivityStats(ctx context.Context) (*ProductivityStats, error)
 }
 
 type client struct {
@@ -75,6 +88,37 @@ type ListTasksOptions struct {
 	Limit  int
 }
 
+// CompletedTasksOptions selects completed tasks by completion date, the range may span up to 3 months
+type CompletedTasksOptions struct {
+	Since     time.Time
+	Until     time.Time
+	ProjectID string
+	ParentID  string
+	Cursor    string
+	Limit     int
+}
+
+type CompletedTasksResponse struct {
+	Items      []Task `json:"items"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// ListAllCompletedTasks pages through ListCompletedTasks, returning every task completed in the range of options
+func ListAllCompletedTasks(ctx context.Context, client Client, options CompletedTasksOptions) ([]Task, error) {
+	var tasks []Task
+	for {
+		resp, err := client.ListCompletedTasks(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, resp.Items...)
+		if resp.NextCursor == "" {
+			return tasks, nil
+		}
+		options.Cursor = resp.NextCursor
+	}
+}
+
 type CreateTaskOptions struct {
 	Content      string   `json:"content"`
 	Description  string   `json:"description,omitempty"`
@@ -95,6 +139,30 @@ type CreateTaskOptions struct {
 	DeadlineLang string   `json:"deadline_lang,omitempty"`
 }
 
+// UpdateTaskOptions holds the fields to change on a task, nil fields are left untouched
+type UpdateTaskOptions struct {
+	Content      *string   `json:"content,omitempty"`
+	Description  *string   `json:"description,omitempty"`
+	Labels       *[]string `json:"labels,omitempty"`
+	Priority     *int      `json:"priority,omitempty"`
+	AssigneeID   *int      `json:"assignee_id,omitempty"`
+	DueDate      *string   `json:"due_date,omitempty"`
+	DueString    *string   `json:"due_string,omitempty"`
+	DueDatetime  *string   `json:"due_datetime,omitempty"`
+	DueLang      *string   `json:"due_lang,omitempty"`
+	Duration     *int      `json:"duration,omitempty"`
+	DurationUnit *string   `json:"duration_unit,omitempty"`
+	DeadlineDate *string   `json:"deadline_date,omitempty"`
+	DeadlineLang *string   `json:"deadline_lang,omitempty"`
+}
+
+// MoveTaskOptions gives the destination of a moved task, only one field may be set
+type MoveTaskOptions struct {
+	ProjectID string `json:"project_id,omitempty"`
+	SectionID string `json:"section_id,omitempty"`
+	ParentID  string `json:"parent_id,omitempty"`
+}
+
 // Project types
 type Project struct {
 	ID             string         `json:"id"`
@@ -121,11 +189,76 @@ type Project struct {
 	IsShared       bool           `json:"is_shared"`
 }
 
+// Label is a personal label
+type Label struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Color      string `json:"color"`
+	ItemOrder  int    `json:"item_order"`
+	IsDeleted  bool   `json:"is_deleted"`
+	IsFavorite bool   `json:"is_favorite"`
+}
+
+// Section types
+type Section struct {
+	ID           string `json:"id"`
+	ProjectID    string `json:"project_id"`
+	Name         string `json:"name"`
+	SectionOrder int    `json:"section_order"`
+	IsArchived   bool   `json:"is_archived"`
+	IsDeleted    bool   `json:"is_deleted"`
+	IsCollapsed  bool   `json:"is_collapsed"`
+	AddedAt      string `json:"added_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
 type ProjectsResponse struct {
 	Results    []Project `json:"results"`
 	NextCursor string    `json:"next_cursor"`
 }
 
+// Sync types
+type SyncRequest struct {
+	SyncToken     string
+	ResourceTypes []string
+	Commands      []Command
+}
+
+type Command struct {
+	Type   string         `json:"type"`
+	UUID   string         `json:"uuid"`
+	TempID string         `json:"temp_id,omitempty"`
+	Args   map[string]any `json:"args"`
+}
+
+type SyncResponse struct {
+	SyncToken     string                     `json:"sync_token"`
+	FullSync      bool                       `json:"full_sync"`
+	Items         []Task                     `json:"items"`
+	Projects      []Project                  `json:"projects"`
+	Sections      []Section                  `json:"sections"`
+	Labels        []Label                    `json:"labels"`
+	SyncStatus    map[string]json.RawMessage `json:"sync_status"`
+	TempIDMapping map[string]string          `json:"temp_id_mapping"`
+}
+
+// User types
+type User struct {
+	ID             string         `json:"id"`
+	Email          string         `json:"email"`
+	FullName       string         `json:"full_name"`
+	InboxProjectID string         `json:"inbox_project_id"`
+	IsPremium      bool           `json:"is_premium"`
+	Karma          float64        `json:"karma"`
+	KarmaTrend     string         `json:"karma_trend"`
+	DailyGoal      int            `json:"daily_goal"`
+	WeeklyGoal     int            `json:"weekly_goal"`
+	DaysOff        []int          `json:"days_off"`
+	StartDay       int            `json:"start_day"`
+	Lang           string         `json:"lang"`
+	TZInfo         map[string]any `json:"tz_info"`
+}
+
 // OAuth types
 type TokenResponse struct {
 	AccessToken string `json:"access_token"`
@@ -185,6 +318,61 @@ func (c *client) ListTasks(ctx context.Context, options *ListTasksOptions) (*Tas
 	return &tasksResp, nil
 }
 
+func (c *client) ListCompletedTasks(ctx context.Context, options CompletedTasksOptions) (*CompletedTasksResponse, error) {
+	params := url.Values{
+		"since": {options.Since.UTC().Format(time.RFC3339)},
+		"until": {options.Until.UTC().Format(time.RFC3339)},
+	}
+	if options.ProjectID != "" {
+		params.Set("project_id", options.ProjectID)
+	}
+	if options.ParentID != "" {
+		params.Set("parent_id", options.ParentID)
+	}
+	if options.Cursor != "" {
+		params.Set("cursor", options.Cursor)
+	}
+	if options.Limit > 0 {
+		params.Set("limit", strconv.Itoa(options.Limit))
+	}
+
+	apiURL := BaseURL + "/tasks/completed/by_completion_date?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 401:
+		return nil, fmt.Errorf("unauthorized: please login again")
+	case 200:
+		// Success, continue
+	default:
+		return nil, fmt.Errorf("API error: %d %s", resp.StatusCode, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var completedResp CompletedTasksResponse
+	if err := json.Unmarshal(body, &completedResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &completedResp, nil
+}
+
 func (c *client) CloseTask(ctx context.Context, taskID string) error {
 	apiURL := BaseURL + "/tasks/" + taskID + "/close"
 	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, nil)
@@ -229,9 +417,79 @@ func (c *client) ReopenTask(ctx context.Context, taskID string) error {
 	return nil
 }
 
-func (c *client) CreateTask(ctx context.Context, options CreateTaskOptions) error {
+func (c *client) CreateTask(ctx context.Context, options CreateTaskOptions) (*Task, error) {
 	apiURL := BaseURL + "/tasks"
 
+	requestBody, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error: %d %s: %s", resp.StatusCode, resp.Status, body)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var task Task
+	if err := json.Unmarshal(body, &task); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &task, nil
+}
+
+func (c *client) UpdateTask(ctx context.Context, taskID string, options UpdateTaskOptions) error {
+	apiURL := BaseURL + "/tasks/" + taskID
+
+	requestBody, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error: %d %s: %s", resp.StatusCode, resp.Status, body)
+	}
+
+	return nil
+}
+
+func (c *client) MoveTask(ctx context.Context, taskID string, options MoveTaskOptions) error {
+	apiURL := BaseURL + "/tasks/" + taskID + "/move"
+
 	requestBody, err := json.Marshal(options)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request body: %w", err)
@@ -298,6 +556,103 @@ func (c *client) ListProjects(ctx context.Context) (*ProjectsResponse, error) {
 	return &projResp, nil
 }
 
+// Sync methods
+func (c *client) Sync(ctx context.Context, request SyncRequest) (*SyncResponse, error) {
+	data := url.Values{}
+	if request.SyncToken != "" {
+		data.Set("sync_token", request.SyncToken)
+	}
+	if len(request.ResourceTypes) > 0 {
+		resourceTypes, err := json.Marshal(request.ResourceTypes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal resource types: %w", err)
+		}
+		data.Set("resource_types", string(resourceTypes))
+	}
+	if len(request.Commands) > 0 {
+		commands, err := json.Marshal(request.Commands)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal commands: %w", err)
+		}
+		data.Set("commands", string(commands))
+	}
+
+	apiURL := BaseURL + "/sync"
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 401:
+		return nil, fmt.Errorf("unauthorized: please login again")
+	case 200:
+		// Success, continue
+	default:
+		return nil, &APIError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var syncResp SyncResponse
+	if err := json.Unmarshal(body, &syncResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &syncResp, nil
+}
+
+// User methods
+func (c *client) GetUser(ctx context.Context) (*User, error) {
+	apiURL := BaseURL + "/user"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 401:
+		return nil, fmt.Errorf("unauthorized: invalid or revoked token")
+	case 200:
+		// Success, continue
+	default:
+		return nil, fmt.Errorf("API error: %d %s", resp.StatusCode, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var user User
+	if err := json.Unmarshal(body, &user); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &user, nil
+}
+
 // OAuth methods
 func (c *client) ExchangeCodeForToken(code, redirectURI, clientID, clientSecret string) (*TokenResponse, error) {
 	data := url.Values{
@@ -332,4 +687,76 @@ func (c *client) ExchangeCodeForToken(code, redirectURI, clientID, clientSecret
 	}
 
 	return &tokenResp, nil
-}
\ No newline at end of file
+}
+
+func (c *client) RevokeToken(ctx context.Context, clientID, clientSecret, accessToken string) error {
+	params := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"access_token":  {accessToken},
+	}
+
+	apiURL := BaseURL + "/access_tokens?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, "DELETE", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error: %d %s: %s", resp.StatusCode, resp.Status, body)
+	}
+
+	return nil
+}
+
+func (c *client) MigratePersonalToken(ctx context.Context, personalToken, clientID, clientSecret, scope string) (*TokenResponse, error) {
+	requestBody, err := json.Marshal(map[string]string{
+		"client_id":     clientID,
+		"client_secret": clientSecret,
+		"scope":         scope,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	apiURL := BaseURL + "/access_tokens/migrate_personal_token?" + url.Values{"personal_token": {personalToken}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error: %d %s: %s", resp.StatusCode, resp.Status, body)
+	}
+
+	var tokenResp TokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("empty access token received")
+	}
+
+	return &tokenResp, nil
+}
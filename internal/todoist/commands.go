@@ -0,0 +1,280 @@
+package todoist
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// NewUUID returns a random version 4 UUID, used for command UUIDs and temporary IDs
+func NewUUID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// IsNetworkError reports whether err means Todoist could not be reached at all
+func IsNetworkError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// APIError is an unexpected HTTP status from Todoist
+type APIError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %d %s", e.StatusCode, e.Status)
+}
+
+// IsTemporaryError reports whether a request that failed with err may succeed later unchanged:
+// Todoist could not be reached, timed out, was rate limited or had a server error
+func IsTemporaryError(err error) bool {
+	if IsNetworkError(err) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500)
+}
+
+// AddTaskCommand builds an item_add Sync command with a fresh UUID and temporary ID
+func AddTaskCommand(options CreateTaskOptions) Command {
+	args := map[string]any{"content": options.Content}
+	if options.Description != "" {
+		args["description"] = options.Description
+	}
+	if options.ProjectID != "" {
+		args["project_id"] = options.ProjectID
+	}
+	if options.SectionID != "" {
+		args["section_id"] = options.SectionID
+	}
+	if options.ParentID != "" {
+		args["parent_id"] = options.ParentID
+	}
+	if options.Order != 0 {
+		args["child_order"] = options.Order
+	}
+	if len(options.Labels) > 0 {
+		args["labels"] = options.Labels
+	}
+	if options.Priority != 0 {
+		args["priority"] = options.Priority
+	}
+	if options.AssigneeID != 0 {
+		args["responsible_uid"] = strconv.Itoa(options.AssigneeID)
+	}
+	if due := dueArg(options.DueString, options.DueDate, options.DueDatetime, options.DueLang); due != nil {
+		args["due"] = due
+	}
+	if options.DeadlineDate != "" {
+		args["deadline"] = deadlineArg(options.DeadlineDate, options.DeadlineLang)
+	}
+	if options.Duration != 0 {
+		args["duration"] = durationArg(options.Duration, options.DurationUnit)
+	}
+
+	return Command{
+		Type:   "item_add",
+		UUID:   NewUUID(),
+		TempID: NewUUID(),
+		Args:   args,
+	}
+}
+
+// UpdateTaskCommand builds an item_update Sync command, empty values clear the field
+func UpdateTaskCommand(taskID string, options UpdateTaskOptions) Command {
+	args := map[string]any{"id": taskID}
+	if options.Content != nil {
+		args["content"] = *options.Content
+	}
+	if options.Description != nil {
+		args["description"] = *options.Description
+	}
+	if options.Labels != nil {
+		args["labels"] = *options.Labels
+	}
+	if options.Priority != nil {
+		args["priority"] = *options.Priority
+	}
+	if options.AssigneeID != nil {
+		if *options.AssigneeID == 0 {
+			args["responsible_uid"] = nil
+		} else {
+			args["responsible_uid"] = strconv.Itoa(*options.AssigneeID)
+		}
+	}
+	if options.DueString != nil || options.DueDate != nil || options.DueDatetime != nil {
+		due := dueArg(deref(options.DueString), deref(options.DueDate), deref(options.DueDatetime), deref(options.DueLang))
+		if due == nil {
+			args["due"] = nil
+		} else {
+			args["due"] = due
+		}
+	}
+	if options.DeadlineDate != nil {
+		if *options.DeadlineDate == "" {
+			args["deadline"] = nil
+		} else {
+			args["deadline"] = deadlineArg(*options.DeadlineDate, deref(options.DeadlineLang))
+		}
+	}
+	if options.Duration != nil {
+		if *options.Duration == 0 {
+			args["duration"] = nil
+		} else {
+			args["duration"] = durationArg(*options.Duration, deref(options.DurationUnit))
+		}
+	}
+
+	return Command{
+		Type: "item_update",
+		UUID: NewUUID(),
+		Args: args,
+	}
+}
+
+// MoveTaskCommand builds an item_move Sync command
+func MoveTaskCommand(taskID string, options MoveTaskOptions) Command {
+	args := map[string]any{"id": taskID}
+	switch {
+	case options.ParentID != "":
+		args["parent_id"] = options.ParentID
+	case options.SectionID != "":
+		args["section_id"] = options.SectionID
+	case options.ProjectID != "":
+		args["project_id"] = options.ProjectID
+	}
+
+	return Command{
+		Type: "item_move",
+		UUID: NewUUID(),
+		Args: args,
+	}
+}
+
+// CloseTaskCommand builds an item_close Sync command
+func CloseTaskCommand(taskID string) Command {
+	return Command{
+		Type: "item_close",
+		UUID: NewUUID(),
+		Args: map[string]any{"id": taskID},
+	}
+}
+
+// ReopenTaskCommand builds an item_uncomplete Sync command
+func ReopenTaskCommand(taskID string) Command {
+	return Command{
+		Type: "item_uncomplete",
+		UUID: NewUUID(),
+		Args: map[string]any{"id": taskID},
+	}
+}
+
+// AddProjectCommand builds a project_add Sync command with a temporary ID, parentID may be empty or a temporary ID
+func AddProjectCommand(name, parentID string) Command {
+	args := map[string]any{"name": name}
+	if parentID != "" {
+		args["parent_id"] = parentID
+	}
+	return Command{
+		Type:   "project_add",
+		UUID:   NewUUID(),
+		TempID: NewUUID(),
+		Args:   args,
+	}
+}
+
+// AddSectionCommand builds a section_add Sync command with a temporary ID, projectID may be a temporary ID
+func AddSectionCommand(name, projectID string) Command {
+	return Command{
+		Type:   "section_add",
+		UUID:   NewUUID(),
+		TempID: NewUUID(),
+		Args:   map[string]any{"name": name, "project_id": projectID},
+	}
+}
+
+// AddLabelCommand builds a label_add Sync command for a personal label
+func AddLabelCommand(name string) Command {
+	return Command{
+		Type:   "label_add",
+		UUID:   NewUUID(),
+		TempID: NewUUID(),
+		Args:   map[string]any{"name": name},
+	}
+}
+
+// StatusError returns the error message of a sync_status entry, or "" when it is "ok"
+func StatusError(status json.RawMessage) string {
+	var ok string
+	if err := json.Unmarshal(status, &ok); err == nil {
+		if ok == "ok" {
+			return ""
+		}
+		return ok
+	}
+
+	var e struct {
+		ErrorCode int    `json:"error_code"`
+		Error     string `json:"error"`
+	}
+	if err := json.Unmarshal(status, &e); err != nil {
+		return string(status)
+	}
+	return fmt.Sprintf("%s (code %d)", e.Error, e.ErrorCode)
+}
+
+func dueArg(dueString, dueDate, dueDatetime, dueLang string) map[string]any {
+	due := map[string]any{}
+	switch {
+	case dueDatetime != "":
+		due["date"] = dueDatetime
+	case dueDate != "":
+		due["date"] = dueDate
+	case dueString != "":
+		due["string"] = dueString
+	default:
+		return nil
+	}
+	if dueLang != "" {
+		due["lang"] = dueLang
+	}
+	return due
+}
+
+func deadlineArg(date, lang string) map[string]any {
+	deadline := map[string]any{"date": date}
+	if lang != "" {
+		deadline["lang"] = lang
+	}
+	return deadline
+}
+
+func durationArg(amount int, unit string) map[string]any {
+	if unit == "" {
+		unit = "minute"
+	}
+	return map[string]any{"amount": amount, "unit": unit}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,148 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ErrNotFound is returned when Todoist has no object with the requested ID
+var ErrNotFound = errors.New("not found")
+
+// Comment is a note on a task
+type Comment struct {
+	ID             string              `json:"id"`
+	ItemID         string              `json:"item_id"`
+	PostedUID      string              `json:"posted_uid"`
+	Content        string              `json:"content"`
+	FileAttachment *Attachment         `json:"file_attachment"`
+	IsDeleted      bool                `json:"is_deleted"`
+	PostedAt       string              `json:"posted_at"`
+	Reactions      map[string][]string `json:"reactions"`
+}
+
+// Attachment is a file or link attached to a comment
+type Attachment struct {
+	FileName     string `json:"file_name"`
+	FileType     string `json:"file_type"`
+	FileSize     int    `json:"file_size"`
+	FileURL      string `json:"file_url"`
+	ResourceType string `json:"resource_type"`
+	UploadState  string `json:"upload_state"`
+}
+
+type ListCommentsOptions struct {
+	TaskID string
+	Cursor string
+	Limit  int
+}
+
+type CommentsResponse struct {
+	Results    []Comment `json:"results"`
+	NextCursor string    `json:"next_cursor"`
+}
+
+// Collaborator is a user sharing a project
+type Collaborator struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type ListCollaboratorsOptions struct {
+	ProjectID string
+	Cursor    string
+	Limit     int
+}
+
+type CollaboratorsResponse struct {
+	Results    []Collaborator `json:"results"`
+	NextCursor string         `json:"next_cursor"`
+}
+
+func (c *client) GetTask(ctx context.Context, taskID string) (*Task, error) {
+	var task Task
+	if err := c.get(ctx, BaseURL+"/tasks/"+url.PathEscape(taskID), &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (c *client) ListComments(ctx context.Context, options ListCommentsOptions) (*CommentsResponse, error) {
+	params := url.Values{"task_id": {options.TaskID}}
+	if options.Cursor != "" {
+		params.Set("cursor", options.Cursor)
+	}
+	if options.Limit > 0 {
+		params.Set("limit", strconv.Itoa(options.Limit))
+	}
+
+	var commentsResp CommentsResponse
+	if err := c.get(ctx, BaseURL+"/comments?"+params.Encode(), &commentsResp); err != nil {
+		return nil, err
+	}
+	return &commentsResp, nil
+}
+
+func (c *client) ListCollaborators(ctx context.Context, options ListCollaboratorsOptions) (*CollaboratorsResponse, error) {
+	params := url.Values{}
+	if options.Cursor != "" {
+		params.Set("cursor", options.Cursor)
+	}
+	if options.Limit > 0 {
+		params.Set("limit", strconv.Itoa(options.Limit))
+	}
+
+	apiURL := BaseURL + "/projects/" + url.PathEscape(options.ProjectID) + "/collaborators"
+	if len(params) > 0 {
+		apiURL += "?" + params.Encode()
+	}
+
+	var collaboratorsResp CollaboratorsResponse
+	if err := c.get(ctx, apiURL, &collaboratorsResp); err != nil {
+		return nil, err
+	}
+	return &collaboratorsResp, nil
+}
+
+// get sends an authorized GET request and decodes the JSON response into v
+func (c *client) get(ctx context.Context, apiURL string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 401:
+		return fmt.Errorf("unauthorized: please login again")
+	case 404:
+		return ErrNotFound
+	case 200:
+		// Success, continue
+	default:
+		return fmt.Errorf("API error: %d %s", resp.StatusCode, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
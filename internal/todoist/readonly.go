@@ -0,0 +1,59 @@
+package todoist
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrReadOnly is returned for writes made through a read-only client
+var ErrReadOnly = errors.New("read-only mode: refusing to modify Todoist data, drop --read-only or set read_only to false")
+
+// readOnlyClient refuses every call that would change data, before any request is sent
+type readOnlyClient struct {
+	Client
+}
+
+// NewReadOnlyClient wraps inner so all writes fail with ErrReadOnly
+func NewReadOnlyClient(inner Client) Client {
+	return &readOnlyClient{Client: inner}
+}
+
+func (c *readOnlyClient) ExchangeCodeForToken(code, redirectURI, clientID, clientSecret string) (*TokenResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyClient) RevokeToken(ctx context.Context, clientID, clientSecret, accessToken string) error {
+	return ErrReadOnly
+}
+
+func (c *readOnlyClient) MigratePersonalToken(ctx context.Context, personalToken, clientID, clientSecret, scope string) (*TokenResponse, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyClient) CloseTask(ctx context.Context, taskID string) error {
+	return ErrReadOnly
+}
+
+func (c *readOnlyClient) ReopenTask(ctx context.Context, taskID string) error {
+	return ErrReadOnly
+}
+
+func (c *readOnlyClient) CreateTask(ctx context.Context, options CreateTaskOptions) (*Task, error) {
+	return nil, ErrReadOnly
+}
+
+func (c *readOnlyClient) UpdateTask(ctx context.Context, taskID string, options UpdateTaskOptions) error {
+	return ErrReadOnly
+}
+
+func (c *readOnlyClient) MoveTask(ctx context.Context, taskID string, options MoveTaskOptions) error {
+	return ErrReadOnly
+}
+
+// Sync allows reads but refuses requests carrying commands
+func (c *readOnlyClient) Sync(ctx context.Context, request SyncRequest) (*SyncResponse, error) {
+	if len(request.Commands) > 0 {
+		return nil, ErrReadOnly
+	}
+	return c.Client.Sync(ctx, request)
+}
@@ -0,0 +1,217 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// OAuth permission scopes
+const (
+	ScopeTaskAdd       = "task:add"
+	ScopeDataRead      = "data:read"
+	ScopeDataReadWrite = "data:read_write"
+	ScopeDataDelete    = "data:delete"
+	ScopeProjectDelete = "project:delete"
+	ScopeBackupsRead   = "backups:read"
+)
+
+// DefaultScopes are requested when no scopes are configured
+const DefaultScopes = ScopeDataReadWrite + "," + ScopeDataDelete
+
+// Scopes lists every scope Todoist accepts
+var Scopes = []string{ScopeTaskAdd, ScopeDataRead, ScopeDataReadWrite, ScopeDataDelete, ScopeProjectDelete, ScopeBackupsRead}
+
+// impliedScopes are the scopes included in a broader one
+var impliedScopes = map[string][]string{
+	ScopeDataReadWrite: {ScopeTaskAdd, ScopeDataRead},
+}
+
+// ParseScopes splits a comma separated scope list and checks every entry is known
+func ParseScopes(s string) ([]string, error) {
+	var scopes []string
+	for _, scope := range strings.Split(s, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if !slices.Contains(Scopes, scope) {
+			return nil, fmt.Errorf("unknown scope %q, valid scopes: %s", scope, strings.Join(Scopes, ", "))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("no scopes given")
+	}
+	return scopes, nil
+}
+
+// HasScope reports whether the granted scopes include required, directly or through a broader scope.
+// A nil list means the scopes are unknown, as for personal API tokens, and allows everything.
+func HasScope(granted []string, required string) bool {
+	if granted == nil {
+		return true
+	}
+	for _, scope := range granted {
+		if scope == required || slices.Contains(impliedScopes[scope], required) {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingScopes returns the required scopes not included in granted
+func MissingScopes(granted []string, required ...string) []string {
+	var missing []string
+	for _, scope := range required {
+		if !HasScope(granted, scope) && !slices.Contains(missing, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// ScopeError is returned when the token lacks the scopes an operation needs
+type ScopeError struct {
+	Granted []string
+	Missing []string
+}
+
+func (e *ScopeError) Error() string {
+	scopes := append(slices.Clone(e.Granted), e.Missing...)
+	return fmt.Sprintf("the access token lacks the %s scope, log in again with: todoist auth --scope %s",
+		strings.Join(e.Missing, ", "), strings.Join(scopes, ","))
+}
+
+// scopedClient refuses calls the token's scopes do not allow before they reach Todoist
+type scopedClient struct {
+	Client
+	scopes []string
+}
+
+// NewScopedClient wraps inner so operations outside the granted scopes fail with a ScopeError
+func NewScopedClient(inner Client, scopes []string) Client {
+	return &scopedClient{Client: inner, scopes: scopes}
+}
+
+func (c *scopedClient) require(required ...string) error {
+	if missing := MissingScopes(c.scopes, required...); len(missing) > 0 {
+		return &ScopeError{Granted: c.scopes, Missing: missing}
+	}
+	return nil
+}
+
+func (c *scopedClient) ListTasks(ctx context.Context, options *ListTasksOptions) (*TasksResponse, error) {
+	if err := c.require(ScopeDataRead); err != nil {
+		return nil, err
+	}
+	return c.Client.ListTasks(ctx, options)
+}
+
+func (c *scopedClient) ListCompletedTasks(ctx context.Context, options CompletedTasksOptions) (*CompletedTasksResponse, error) {
+	if err := c.require(ScopeDataRead); err != nil {
+		return nil, err
+	}
+	return c.Client.ListCompletedTasks(ctx, options)
+}
+
+func (c *scopedClient) GetTask(ctx context.Context, taskID string) (*Task, error) {
+	if err := c.require(ScopeDataRead); err != nil {
+		return nil, err
+	}
+	return c.Client.GetTask(ctx, taskID)
+}
+
+func (c *scopedClient) ListComments(ctx context.Context, options ListCommentsOptions) (*CommentsResponse, error) {
+	if err := c.require(ScopeDataRead); err != nil {
+		return nil, err
+	}
+	return c.Client.ListComments(ctx, options)
+}
+
+func (c *scopedClient) ListCollaborators(ctx context.Context, options ListCollaboratorsOptions) (*CollaboratorsResponse, error) {
+	if err := c.require(ScopeDataRead); err != nil {
+		return nil, err
+	}
+	return c.Client.ListCollaborators(ctx, options)
+}
+
+func (c *scopedClient) GetProductivityStats(ctx context.Context) (*ProductivityStats, error) {
+	if err := c.require(ScopeDataRead); err != nil {
+		return nil, err
+	}
+	return c.Client.GetProductivityStats(ctx)
+}
+
+func (c *scopedClient) ListProjects(ctx context.Context) (*ProjectsResponse, error) {
+	if err := c.require(ScopeDataRead); err != nil {
+		return nil, err
+	}
+	return c.Client.ListProjects(ctx)
+}
+
+func (c *scopedClient) CloseTask(ctx context.Context, taskID string) error {
+	if err := c.require(ScopeDataReadWrite); err != nil {
+		return err
+	}
+	return c.Client.CloseTask(ctx, taskID)
+}
+
+func (c *scopedClient) ReopenTask(ctx context.Context, taskID string) error {
+	if err := c.require(ScopeDataReadWrite); err != nil {
+		return err
+	}
+	return c.Client.ReopenTask(ctx, taskID)
+}
+
+func (c *scopedClient) CreateTask(ctx context.Context, options CreateTaskOptions) (*Task, error) {
+	if err := c.require(ScopeTaskAdd); err != nil {
+		return nil, err
+	}
+	return c.Client.CreateTask(ctx, options)
+}
+
+func (c *scopedClient) UpdateTask(ctx context.Context, taskID string, options UpdateTaskOptions) error {
+	if err := c.require(ScopeDataReadWrite); err != nil {
+		return err
+	}
+	return c.Client.UpdateTask(ctx, taskID, options)
+}
+
+func (c *scopedClient) MoveTask(ctx context.Context, taskID string, options MoveTaskOptions) error {
+	if err := c.require(ScopeDataReadWrite); err != nil {
+		return err
+	}
+	return c.Client.MoveTask(ctx, taskID, options)
+}
+
+func (c *scopedClient) Sync(ctx context.Context, request SyncRequest) (*SyncResponse, error) {
+	var required []string
+	if len(request.ResourceTypes) > 0 {
+		required = append(required, ScopeDataRead)
+	}
+	for _, cmd := range request.Commands {
+		required = append(required, CommandScope(cmd.Type))
+	}
+	if err := c.require(required...); err != nil {
+		return nil, err
+	}
+	return c.Client.Sync(ctx, request)
+}
+
+// CommandScope returns the scope needed to run a Sync command of the given type
+func CommandScope(commandType string) string {
+	switch {
+	case commandType == "item_add":
+		return ScopeTaskAdd
+	case commandType == "project_delete":
+		return ScopeProjectDelete
+	case strings.HasSuffix(commandType, "_delete"):
+		return ScopeDataDelete
+	default:
+		return ScopeDataReadWrite
+	}
+}
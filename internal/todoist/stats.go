@@ -0,0 +1,123 @@
+package todoist
+
+import "context"
+
+// ProductivityStats is the user's completion history, karma and goals
+type ProductivityStats struct {
+	CompletedCount  int               `json:"completed_count"`
+	Karma           float64           `json:"karma"`
+	KarmaTrend      string            `json:"karma_trend"`
+	KarmaLastUpdate float64           `json:"karma_last_update"`
+	DaysItems       []DayStats        `json:"days_items"`
+	WeekItems       []WeekStats       `json:"week_items"`
+	KarmaGraphData  []KarmaGraphPoint `json:"karma_graph_data"`
+	KarmaUpdates    []KarmaUpdate     `json:"karma_update_reasons"`
+	ProjectColors   map[string]string `json:"project_colors"`
+	Goals           ProductivityGoals `json:"goals"`
+}
+
+// DayStats counts the tasks completed on one day, in total and per project
+type DayStats struct {
+	Date           string         `json:"date"`
+	TotalCompleted int            `json:"total_completed"`
+	Items          []ProjectCount `json:"items"`
+}
+
+// WeekStats counts the tasks completed in the week from From to To
+type WeekStats struct {
+	From           string         `json:"from"`
+	To             string         `json:"to"`
+	TotalCompleted int            `json:"total_completed"`
+	Items          []ProjectCount `json:"items"`
+}
+
+// ProjectCount is the number of tasks completed in the project with ID
+type ProjectCount struct {
+	ID        string `json:"id"`
+	Completed int    `json:"completed"`
+}
+
+// KarmaGraphPoint is the average karma on a date
+type KarmaGraphPoint struct {
+	Date     string  `json:"date"`
+	KarmaAvg float64 `json:"karma_avg"`
+}
+
+// KarmaUpdate is a change in karma
+type KarmaUpdate struct {
+	Time          string  `json:"time"`
+	NewKarma      float64 `json:"new_karma"`
+	PositiveKarma float64 `json:"positive_karma"`
+	NegativeKarma float64 `json:"negative_karma"`
+}
+
+// ProductivityGoals are the user's daily and weekly goals and the streaks of meeting them
+type ProductivityGoals struct {
+	DailyGoal           int    `json:"daily_goal"`
+	WeeklyGoal          int    `json:"weekly_goal"`
+	IgnoreDays          []int  `json:"ignore_days"`
+	VacationMode        int    `json:"vacation_mode"`
+	KarmaDisabled       int    `json:"karma_disabled"`
+	CurrentDailyStreak  Streak `json:"current_daily_streak"`
+	MaxDailyStreak      Streak `json:"max_daily_streak"`
+	CurrentWeeklyStreak Streak `json:"current_weekly_streak"`
+	MaxWeeklyStreak     Streak `json:"max_weekly_streak"`
+}
+
+// Streak is a run of days or weeks in which the goal was met
+type Streak struct {
+	Count int    `json:"count"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// UpdateGoalsOptions holds the goal settings to change, nil fields are left untouched
+type UpdateGoalsOptions struct {
+	DailyGoal  *int
+	WeeklyGoal *int
+	// IgnoreDays are the days of the week, 1 for Monday to 7 for Sunday, that do not count against streaks
+	IgnoreDays    *[]int
+	VacationMode  *bool
+	KarmaDisabled *bool
+}
+
+func (c *client) GetProductivityStats(ctx context.Context) (*ProductivityStats, error) {
+	var stats ProductivityStats
+	if err := c.get(ctx, BaseURL+"/tasks/completed/stats", &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// UpdateGoalsCommand builds an update_goals Sync command
+func UpdateGoalsCommand(options UpdateGoalsOptions) Command {
+	args := map[string]any{}
+	if options.DailyGoal != nil {
+		args["daily_goal"] = *options.DailyGoal
+	}
+	if options.WeeklyGoal != nil {
+		args["weekly_goal"] = *options.WeeklyGoal
+	}
+	if options.IgnoreDays != nil {
+		args["ignore_days"] = *options.IgnoreDays
+	}
+	if options.VacationMode != nil {
+		args["vacation_mode"] = boolInt(*options.VacationMode)
+	}
+	if options.KarmaDisabled != nil {
+		args["karma_disabled"] = boolInt(*options.KarmaDisabled)
+	}
+
+	return Command{
+		Type: "update_goals",
+		UUID: NewUUID(),
+		Args: args,
+	}
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -2,30 +2,91 @@ package ui
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/queue"
 	"github.com/mdjarv/todoist-cli/internal/todoist"
 )
 
-// reloadCmd fetches projects and tasks, then returns ReloadMsg
-func reloadCmd(client todoist.Client) tea.Cmd {
+// requestTimeout bounds the changes sent from the TUI, so a network that never answers does not leave them hanging.
+// Changes that time out stay queued.
+const requestTimeout = 15 * time.Second
+
+// createTaskCmd creates a task and returns taskCreatedMsg
+func createTaskCmd(client todoist.Client, options todoist.CreateTaskOptions) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-		projectsResp, err := client.ListProjects(ctx)
-		if err != nil {
-			return err
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+		_, err := client.CreateTask(ctx, options)
+		if errors.Is(err, queue.ErrQueued) {
+			return taskCreatedMsg{queued: true}
+		}
+		return taskCreatedMsg{err: err}
+	}
+}
+
+// toggleTaskCmd reopens the task when checked and closes it otherwise, returning taskUpdatedMsg.
+// Once the change reached Todoist the cache is synced, or marked stale when that fails, so other commands see it.
+func toggleTaskCmd(client todoist.Client, store *cache.Cache, taskID string, checked bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		var err error
+		if checked {
+			err = client.ReopenTask(ctx, taskID)
+		} else {
+			err = client.CloseTask(ctx, taskID)
+		}
+		if errors.Is(err, queue.ErrQueued) {
+			return taskUpdatedMsg{taskID: taskID, queued: true}
 		}
-		tasksResp, err := client.ListTasks(ctx, nil)
 		if err != nil {
-			return err
+			return taskFailedMsg{taskID: taskID, err: err}
 		}
-		projectNames := make(map[string]string)
-		for _, p := range projectsResp.Results {
-			projectNames[p.ID] = p.Name
+
+		if err := store.Refresh(ctx, client); err != nil {
+			if err := store.Invalidate(); err != nil {
+				return taskUpdatedMsg{taskID: taskID, err: fmt.Errorf("failed to update cache: %w", err)}
+			}
 		}
+		return taskUpdatedMsg{taskID: taskID}
+	}
+}
+
+// reloadCmd syncs the cache unless offline, then returns ReloadMsg with its contents, any queued changes
+// and the recently completed subtasks
+func reloadCmd(client todoist.Client, store *cache.Cache, journal *queue.Journal, offline bool) tea.Cmd {
+	return func() tea.Msg {
+		var syncErr error
+		var completed []todoist.Task
+		if !offline {
+			// On failure keep going with the cached data, the error is shown alongside it
+			syncErr = store.Refresh(context.Background(), client)
+		}
+		if !offline && syncErr == nil {
+			// Completed subtasks count towards their parents and show up with the completed tasks
+			completed, syncErr = store.CompletedSubtasks(context.Background(), client)
+		}
+		if syncErr == nil {
+			conflicts, err := journal.TakeConflicts()
+			if err != nil {
+				syncErr = err
+			} else if len(conflicts) > 0 {
+				syncErr = fmt.Errorf("%d queued changes were rejected, first: %s", len(conflicts), conflicts[0].Error)
+			}
+		}
+
+		tasks, projectNames := store.Snapshot()
 		return ReloadMsg{
-			Tasks:        tasksResp.Results,
+			Tasks:        append(journal.Apply(tasks), completed...),
 			ProjectNames: projectNames,
+			Pending:      journal.PendingIDs(),
+			Err:          syncErr,
 		}
 	}
 }
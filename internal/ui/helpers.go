@@ -1,78 +1,112 @@
 package ui
 
 import (
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/mdjarv/todoist-cli/internal/query"
 	"github.com/mdjarv/todoist-cli/internal/todoist"
 )
 
-// taskToRow converts a todoist.Task to a table row
-func taskToRow(task todoist.Task, projectNames map[string]string, isUpdating bool, spin spinner.Model) table.Row {
-	done := " "
-	if isUpdating {
-		done = spin.View()
-	} else if task.Checked {
-		done = "✓"
-	}
-	// Use project name if available, fallback to ID or Unknown
-	project := "Unknown"
-	if task.ProjectID != "" {
-		if name, ok := projectNames[task.ProjectID]; ok && name != "" {
-			project = name
-		} else {
-			project = task.ProjectID
-		}
-	}
+// pendingSuffix marks tasks with changes queued while offline
+const pendingSuffix = " (pending)"
 
-	// Format due date
-	due := "No due date"
-	if task.Due != nil {
-		if dateStr, ok := task.Due["date"].(string); ok && dateStr != "" {
-			due = dateStr
-		}
-	}
+// columnDefs are the available table columns keyed by their config name
+var columnDefs = map[string]table.Column{
+	"done":    {Title: "Done", Width: 5},
+	"task":    {Title: "Task", Width: 40},
+	"project": {Title: "Project", Width: 20},
+	"due":     {Title: "Due", Width: 12},
+	"labels":  {Title: "Labels", Width: 20},
+}
 
-	// Format labels
-	labels := strings.Join(task.Labels, ", ")
-	if labels == "" {
-		labels = "No labels"
+// tableColumns returns the table columns for the configured column names, skipping unknown ones
+func tableColumns(names []string) ([]table.Column, []string) {
+	var columns []table.Column
+	var known []string
+	for _, name := range names {
+		if col, ok := columnDefs[name]; ok {
+			columns = append(columns, col)
+			known = append(known, name)
+		}
 	}
+	return columns, known
+}
 
-	return table.Row{
-		done,
-		task.Content,
-		project,
-		due,
-		labels,
-	}
+// rowOptions controls how tasks are rendered as table rows
+type rowOptions struct {
+	columns    []string
+	dateFormat string
 }
 
-func sortTasks(tasks []todoist.Task) {
-	sort.Slice(tasks, func(i, j int) bool {
-		dueI := time.Now().AddDate(100, 0, 0) // A date far in the future
-		if tasks[i].Due != nil {
-			if dateStr, ok := tasks[i].Due["date"].(string); ok {
-				parsed, err := time.Parse("2006-01-02", dateStr)
-				if err == nil {
-					dueI = parsed
-				}
+// taskToRow converts a task in the subtask tree to a table row
+func taskToRow(node query.TreeRow, projectNames map[string]string, opts rowOptions, isUpdating, isPending bool, spin spinner.Model) table.Row {
+	task := node.Task
+	row := make(table.Row, len(opts.columns))
+	for i, col := range opts.columns {
+		switch col {
+		case "done":
+			done := " "
+			if isUpdating {
+				done = spin.View()
+			} else if task.Checked {
+				done = "✓"
 			}
-		}
-
-		dueJ := time.Now().AddDate(100, 0, 0) // A date far in the future
-		if tasks[j].Due != nil {
-			if dateStr, ok := tasks[j].Due["date"].(string); ok {
-				parsed, err := time.Parse("2006-01-02", dateStr)
-				if err == nil {
-					dueJ = parsed
+			row[i] = done
+		case "task":
+			content := node.Content()
+			if isPending {
+				content += pendingSuffix
+			}
+			row[i] = content
+		case "project":
+			// Use project name if available, fallback to ID or Unknown
+			project := "Unknown"
+			if task.ProjectID != "" {
+				if name, ok := projectNames[task.ProjectID]; ok && name != "" {
+					project = name
+				} else {
+					project = task.ProjectID
 				}
 			}
+			row[i] = project
+		case "due":
+			row[i] = formatDue(task, opts.dateFormat)
+		case "labels":
+			labels := strings.Join(task.Labels, ", ")
+			if labels == "" {
+				labels = "No labels"
+			}
+			row[i] = labels
 		}
+	}
+	return row
+}
+
+// formatDue renders the task's due date with layout, keeping the raw value if it cannot be parsed
+func formatDue(task todoist.Task, layout string) string {
+	if task.Due == nil {
+		return "No due date"
+	}
+	dateStr, ok := task.Due["date"].(string)
+	if !ok || dateStr == "" {
+		return "No due date"
+	}
+	if layout == "" {
+		return dateStr
+	}
+	if parsed, err := time.Parse("2006-01-02", dateStr); err == nil {
+		return parsed.Format(layout)
+	}
+	return dateStr
+}
+
+// taskOrder is how top level tasks are sorted, by due date with undated tasks last.
+// Subtasks always follow their child order below the parent.
+var taskOrder = []query.SortKey{{Field: "due"}}
 
-		return dueI.Before(dueJ)
-	})
+func sortTasks(tasks []todoist.Task, projectNames map[string]string) {
+	query.Sort(tasks, taskOrder, projectNames)
 }
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/mdjarv/todoist-cli/internal/config"
+)
+
+// keyMap maps TUI actions to the keys that trigger them
+type keyMap map[string][]string
+
+// newKeyMap builds the key bindings from the tui.keys settings
+func newKeyMap(bindings map[string]string) keyMap {
+	keys := make(keyMap, len(config.TUIActions))
+	for _, action := range config.TUIActions {
+		for _, key := range strings.Split(bindings[action], ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys[action] = append(keys[action], key)
+			}
+		}
+	}
+	return keys
+}
+
+// is reports whether key is bound to action
+func (k keyMap) is(key, action string) bool {
+	return slices.Contains(k[action], key)
+}
+
+// actionHelp describes each action in the help text, in display order
+var actionHelp = []struct{ action, text string }{
+	{"quit", "to quit"},
+	{"filter", "to toggle completed tasks"},
+	{"toggle", "to toggle done"},
+	{"add", "to add a new task"},
+}
+
+// help describes the bound actions
+func (k keyMap) help() string {
+	var parts []string
+	for _, a := range actionHelp {
+		if len(k[a.action]) > 0 {
+			parts = append(parts, k[a.action][0]+" "+a.text)
+		}
+	}
+	return "Press " + strings.Join(parts, ", ") + "."
+}
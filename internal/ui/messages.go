@@ -6,16 +6,35 @@ import "github.com/mdjarv/todoist-cli/internal/todoist"
 type ReloadMsg struct {
 	Tasks        []todoist.Task
 	ProjectNames map[string]string
+	// Pending is a map of task IDs with queued mutations
+	Pending map[string]bool
+	// Err is set when syncing failed and the tasks come from the cache
+	Err error
 }
 
 type toggleDoneMsg struct {
 	taskID string
+	// checked is whether the task was completed before the toggle
+	checked bool
 }
 
 type taskUpdatedMsg struct {
 	taskID string
+	queued bool
+	// err is set when the change was made but the cache could not be updated
+	err error
+}
+
+type taskFailedMsg struct {
+	taskID string
+	err    error
 }
 
 type createTaskMsg struct {
 	options todoist.CreateTaskOptions
 }
+
+type taskCreatedMsg struct {
+	queued bool
+	err    error
+}
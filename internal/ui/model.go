@@ -1,11 +1,17 @@
 package ui
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/query"
+	"github.com/mdjarv/todoist-cli/internal/queue"
 	"github.com/mdjarv/todoist-cli/internal/todoist"
 )
 
@@ -18,6 +24,18 @@ type Model struct {
 	mode string
 	// Client for fetching data
 	Client todoist.Client
+	// Cache holds the locally stored tasks and projects
+	Cache *cache.Cache
+	// Journal holds mutations queued while offline
+	Journal *queue.Journal
+	// offline disables syncing, only the cache is used
+	offline bool
+	// readOnly hides the actions that change tasks
+	readOnly bool
+	// pending is a map of task IDs with queued mutations
+	pending map[string]bool
+	// err is the last error from a background operation
+	err error
 	// Map project IDs to names
 	ProjectNames map[string]string
 	// Spinner for loading indication
@@ -26,48 +44,35 @@ type Model struct {
 	Loading bool
 	// All tasks from the API
 	allTasks []todoist.Task
+	// visible are the tasks shown in the table, in row order
+	visible []todoist.Task
+	// rowOptions holds the configured columns and date format
+	rowOptions rowOptions
+	// keys are the configured key bindings
+	keys keyMap
+	// defaultProject is the project name or ID new tasks are added to
+	defaultProject string
 	// showDone toggles the filter for completed tasks
 	showDone bool
 	// updating is a map of task IDs to a boolean indicating if the task is being updated
 	updating map[string]bool
 
 	// New task input fields
-	taskInput textinput.Model
+	taskInput         textinput.Model
 	taskInputQuitting bool
 }
 
 // NewModel creates a new UI model initialized with default mode and table data
-func NewModel(tasks []todoist.Task, projectNames map[string]string, client todoist.Client) Model {
+func NewModel(tasks []todoist.Task, projectNames map[string]string, pending map[string]bool, client todoist.Client, settings *config.Settings) Model {
 	// Define table columns
-	columns := []table.Column{
-		{Title: "Done", Width: 5},
-		{Title: "Task", Width: 40},
-		{Title: "Project", Width: 20},
-		{Title: "Due", Width: 12},
-		{Title: "Labels", Width: 20},
-	}
+	columns, columnNames := tableColumns(settings.TUI.Columns)
 
 	// Sort tasks by due date
-	sortTasks(tasks)
-
-	// Filter out done tasks by default
-	filteredTasks := []todoist.Task{}
-	for _, task := range tasks {
-		if !task.Checked {
-			filteredTasks = append(filteredTasks, task)
-		}
-	}
-
-	// Convert tasks to table rows
-	rows := make([]table.Row, len(filteredTasks))
-	for i, task := range filteredTasks {
-		rows[i] = taskToRow(task, projectNames, false, spinner.Model{}) // No spinner initially
-	}
+	sortTasks(tasks, projectNames)
 
 	// Create table with styling
 	t := table.New(
 		table.WithColumns(columns),
-		table.WithRows(rows),
 		table.WithFocused(true),
 	)
 
@@ -94,7 +99,7 @@ func NewModel(tasks []todoist.Task, projectNames map[string]string, client todoi
 	ti.CharLimit = 156
 	ti.Width = 20
 
-	return Model{
+	m := Model{
 		mode:              "tasks",
 		Table:             t,
 		Client:            client,
@@ -102,15 +107,65 @@ func NewModel(tasks []todoist.Task, projectNames map[string]string, client todoi
 		Spinner:           sp,
 		Loading:           false,
 		allTasks:          tasks,
+		rowOptions:        rowOptions{columns: columnNames, dateFormat: settings.DateFormat},
+		keys:              newKeyMap(settings.TUI.Keys),
+		defaultProject:    settings.DefaultProject,
 		showDone:          false,
 		updating:          make(map[string]bool),
+		pending:           pending,
 		taskInput:         ti,
 		taskInputQuitting: false,
 	}
+	// Filter out done tasks by default
+	m.refreshRows()
+	return m
+}
+
+// refreshRows rebuilds the table rows from allTasks as a tree of subtasks,
+// hiding completed tasks unless showDone is set
+func (m *Model) refreshRows() {
+	tree := query.Flatten(query.BuildTree(m.allTasks), func(task todoist.Task) bool {
+		return !m.showDone && task.Checked
+	})
+
+	m.visible = make([]todoist.Task, len(tree))
+	rows := make([]table.Row, len(tree))
+	for i, node := range tree {
+		m.visible[i] = node.Task
+		rows[i] = taskToRow(node, m.ProjectNames, m.rowOptions, m.updating[node.Task.ID], m.pending[node.Task.ID], m.Spinner)
+	}
+	m.Table.SetRows(rows)
+}
+
+// selectedTask returns the task under the cursor
+func (m Model) selectedTask() (todoist.Task, bool) {
+	cursor := m.Table.Cursor()
+	if cursor < 0 || cursor >= len(m.visible) {
+		return todoist.Task{}, false
+	}
+	return m.visible[cursor], true
+}
+
+// newTaskProjectID resolves the default_project setting, a project name or ID, to a project ID
+func (m Model) newTaskProjectID() string {
+	if m.defaultProject == "" {
+		return ""
+	}
+	if _, ok := m.ProjectNames[m.defaultProject]; ok {
+		return m.defaultProject
+	}
+	for id, name := range m.ProjectNames {
+		if strings.EqualFold(name, m.defaultProject) {
+			return id
+		}
+	}
+	return ""
 }
 
 // Init is called when the program starts
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.Spinner.Tick, textinput.Blink)
+	if m.offline {
+		return tea.Batch(m.Spinner.Tick, textinput.Blink)
+	}
+	return tea.Batch(m.Spinner.Tick, textinput.Blink, reloadCmd(m.Client, m.Cache, m.Journal, m.offline))
 }
-
@@ -1,37 +1,52 @@
 package ui
 
 import (
-	"context"
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mdjarv/todoist-cli/internal/cache"
+	"github.com/mdjarv/todoist-cli/internal/config"
+	"github.com/mdjarv/todoist-cli/internal/queue"
 	"github.com/mdjarv/todoist-cli/internal/todoist"
 )
 
-// Run starts the Bubble Tea program, accepting a todoist.Client for fetching tasks
-func Run(client todoist.Client) error {
-	ctx := context.Background()
+// Options configures the interactive UI
+type Options struct {
+	// Offline disables syncing, only the cache is used
+	Offline bool
+	// ReadOnly disables the keybindings that change tasks
+	ReadOnly bool
+	// Settings hold the configured columns, keys and date format
+	Settings *config.Settings
+}
 
-	projectsResp, err := client.ListProjects(ctx)
+// Run starts the Bubble Tea program from the local cache, refreshing it in the background unless offline
+func Run(client todoist.Client, journal *queue.Journal, opts Options) error {
+	store, err := cache.Load()
 	if err != nil {
-		return fmt.Errorf("failed to fetch projects: %w", err)
+		return fmt.Errorf("failed to load cache: %w", err)
 	}
-
-	tasksResp, err := client.ListTasks(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to fetch tasks: %w", err)
+	if opts.Offline && store.Empty() {
+		return cache.ErrEmpty
 	}
 
-	// Build projectID to name map
-	projectNames := make(map[string]string)
-	for _, p := range projectsResp.Results {
-		projectNames[p.ID] = p.Name
-	}
+	tasks, projectNames := store.Snapshot()
+	tasks = journal.Apply(tasks)
 
-	m := NewModel(tasksResp.Results, projectNames, client)
+	m := NewModel(tasks, projectNames, journal.PendingIDs(), client, opts.Settings)
+	m.Cache = store
+	m.Journal = journal
+	m.offline = opts.Offline
+	if opts.ReadOnly {
+		m.readOnly = true
+		// Unbind the actions that change tasks
+		delete(m.keys, "add")
+		delete(m.keys, "toggle")
+	}
+	m.Loading = !opts.Offline
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return fmt.Errorf("failed to start UI: %w", err)
 	}
 	return nil
-}
\ No newline at end of file
+}
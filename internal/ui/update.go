@@ -1,14 +1,11 @@
 package ui
 
 import (
-	"context"
 	"fmt"
-	"os"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/mdjarv/todoist-cli/internal/todoist"
-	"github.com/charmbracelet/bubbles/spinner"
-	"github.com/charmbracelet/bubbles/table"
 )
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -18,7 +15,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch msg.Type {
 			case tea.KeyEnter:
 				if content := m.taskInput.Value(); content != "" {
-					options := todoist.CreateTaskOptions{Content: content}
+					options := todoist.CreateTaskOptions{Content: content, ProjectID: m.newTaskProjectID()}
 					return m, func() tea.Msg { return createTaskMsg{options: options} }
 				}
 				// If empty, just do nothing
@@ -38,133 +35,91 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Table.SetHeight(msg.Height - 4)
 		return m, nil
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
+		key := msg.String()
+		switch {
+		case m.keys.is(key, "quit"):
 			return m, tea.Quit
-		case "a":
+		case m.keys.is(key, "add"):
 			m.mode = "new-task"
 			return m, nil
-		case "enter":
-			selectedTask := m.allTasks[m.Table.Cursor()]
+		case m.keys.is(key, "toggle"):
+			selectedTask, ok := m.selectedTask()
+			if !ok {
+				return m, nil
+			}
 			m.updating[selectedTask.ID] = true
 			return m, func() tea.Msg {
-				return toggleDoneMsg{taskID: selectedTask.ID}
+				return toggleDoneMsg{taskID: selectedTask.ID, checked: selectedTask.Checked}
 			}
-		case "f":
+		case m.keys.is(key, "filter"):
 			m.showDone = !m.showDone
-			var filteredTasks []todoist.Task
-			for _, task := range m.allTasks {
-				if m.showDone || !task.Checked {
-					filteredTasks = append(filteredTasks, task)
-				}
-			}
-			rows := make([]table.Row, len(filteredTasks))
-			for i, task := range filteredTasks {
-				rows[i] = taskToRow(task, m.ProjectNames, m.updating[task.ID], m.Spinner)
-			}
-			m.Table.SetRows(rows)
+			m.refreshRows()
 			return m, nil
-		case "r":
+		case m.keys.is(key, "reload"):
 			// Prevent parallel reloads
 			if m.Loading {
 				return m, nil
 			}
 			m.Loading = true
-			return m, tea.Batch(m.Spinner.Tick, reloadCmd(m.Client))
+			return m, tea.Batch(m.Spinner.Tick, reloadCmd(m.Client, m.Cache, m.Journal, m.offline))
 		}
+	case error:
+		m.Loading = false
+		m.err = msg
+		return m, nil
 	case ReloadMsg:
 		m.Loading = false
+		m.err = msg.Err
 		m.allTasks = msg.Tasks
 		m.ProjectNames = msg.ProjectNames
+		m.pending = msg.Pending
 
-		sortTasks(m.allTasks)
-
-		var filteredTasks []todoist.Task
-		for _, task := range m.allTasks {
-			if m.showDone || !task.Checked {
-				filteredTasks = append(filteredTasks, task)
-			}
-		}
-
-		rows := make([]table.Row, len(filteredTasks))
-		for i, task := range filteredTasks {
-			rows[i] = taskToRow(task, msg.ProjectNames, m.updating[task.ID], m.Spinner)
-		}
-		m.Table.SetRows(rows)
+		sortTasks(m.allTasks, m.ProjectNames)
+		m.refreshRows()
 	case toggleDoneMsg:
-		return m, func() tea.Msg {
-			var taskToUpdate todoist.Task
-			for _, task := range m.allTasks {
-				if task.ID == msg.taskID {
-					taskToUpdate = task
-					break
-				}
-			}
-
-			var err error
-			if taskToUpdate.Checked {
-				err = m.Client.ReopenTask(context.Background(), msg.taskID)
-			} else {
-				err = m.Client.CloseTask(context.Background(), msg.taskID)
-			}
-
-			if err != nil {
-				// Handle error, maybe show a message to the user
-				return nil
-			}
-
-			return taskUpdatedMsg{taskID: msg.taskID}
+		return m, toggleTaskCmd(m.Client, m.Cache, msg.taskID, msg.checked)
+	case taskFailedMsg:
+		m.updating[msg.taskID] = false
+		if msg.err != nil {
+			m.err = msg.err
 		}
+		m.refreshRows()
+		return m, nil
 	case taskUpdatedMsg:
 		m.updating[msg.taskID] = false
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		if msg.queued {
+			m.pending[msg.taskID] = true
+		}
 		for i, task := range m.allTasks {
 			if task.ID == msg.taskID {
 				m.allTasks[i].Checked = !m.allTasks[i].Checked
 				break
 			}
 		}
-
-		var filteredTasks []todoist.Task
-		for _, task := range m.allTasks {
-			if m.showDone || !task.Checked {
-				filteredTasks = append(filteredTasks, task)
-			}
-		}
-
-		rows := make([]table.Row, len(filteredTasks))
-		for i, task := range filteredTasks {
-			rows[i] = taskToRow(task, m.ProjectNames, m.updating[task.ID], m.Spinner)
-		}
-		m.Table.SetRows(rows)
-
+		m.refreshRows()
 		return m, nil
-			case createTaskMsg:
-		err := m.Client.CreateTask(context.Background(), msg.options)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, "Error creating task:", err)
+	case createTaskMsg:
+		// Create in the background so a slow network does not freeze the TUI
+		m.mode = "tasks"
+		m.Loading = true
+		return m, tea.Batch(m.Spinner.Tick, createTaskCmd(m.Client, msg.options))
+	case taskCreatedMsg:
+		if msg.err != nil {
+			m.Loading = false
+			m.err = fmt.Errorf("failed to create task: %w", msg.err)
 			return m, nil
 		}
 		m.taskInput.SetValue("") // Clear the input after creating task
-		m.mode = "tasks" // Switch back to tasks view
-		return m, reloadCmd(m.Client)
+		// Show a queued task from the cache without trying to sync again
+		return m, reloadCmd(m.Client, m.Cache, m.Journal, m.offline || msg.queued)
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.Spinner, cmd = m.Spinner.Update(msg)
-		// This is where we'll update the rows that are currently updating
-		rows := m.Table.Rows()
-		for i, row := range rows {
-			// This is a bit of a hack, but we can get the task ID from the row
-			// This is not ideal, but it works for now
-			// A better solution would be to have a direct mapping from row to task ID
-			// but that would require more significant changes
-			// For now, we'll just find the task by content
-			for _, task := range m.allTasks {
-				if task.Content == row[1] && m.updating[task.ID] {
-					rows[i] = taskToRow(task, m.ProjectNames, true, m.Spinner)
-				}
-			}
-		}
-		m.Table.SetRows(rows)
+		// Redraw so rows that are updating show the new spinner frame
+		m.refreshRows()
 		return m, cmd
 	}
 	// Always delegate update to the Bubble Tea table so navigation and selection work
@@ -172,4 +127,3 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.Table, cmd = m.Table.Update(msg)
 	return m, cmd
 }
-
@@ -6,9 +6,17 @@ func (m Model) View() string {
 	var help string
 
 	if m.Loading {
-		help = m.Spinner.View() + " Loading... " + "Press q to quit, f to toggle completed tasks, enter to toggle done, a to add a new task."
+		help = m.Spinner.View() + " Loading... " + m.keys.help()
 	} else {
-		help = "Press q to quit, f to toggle completed tasks, enter to toggle done, a to add a new task."
+		help = m.keys.help()
+	}
+
+	if m.readOnly {
+		help += " Read-only mode."
+	}
+
+	if m.err != nil {
+		help = "Error: " + m.err.Error() + "\n" + help
 	}
 
 	s = m.Table.View() + "\n\n" + help
@@ -19,4 +27,3 @@ func (m Model) View() string {
 
 	return s
 }
-